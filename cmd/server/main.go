@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/prannvs/campus-leave-system/internal/api/handlers"
@@ -9,6 +10,7 @@ import (
 	"github.com/prannvs/campus-leave-system/internal/core"
 	"github.com/prannvs/campus-leave-system/internal/repositories"
 	"github.com/prannvs/campus-leave-system/internal/services"
+	"github.com/prannvs/campus-leave-system/internal/storage"
 	"github.com/prannvs/campus-leave-system/pkg/db"
 )
 
@@ -34,33 +36,159 @@ func main() {
 	database := db.GetDB()
 
 	jwtService := auth.NewJWTService(cfg.JWT.Secret, cfg.JWT.Expiry)
+	gatePassSigner := auth.NewSigner(cfg.GatePass.Secret, "gate-pass")
+	consentSigner := auth.NewSigner(cfg.Consent.Secret, "guardian-consent")
+	invitationSigner := auth.NewSigner(cfg.Invitation.Secret, "invitation")
+	mfaCipher := auth.NewCipher(cfg.MFA.Secret, "mfa-secret")
 
 	userRepo := repositories.NewUserRepository(database)
 	leaveRepo := repositories.NewLeaveRepository(database)
 	attendanceRepo := repositories.NewAttendanceRepository(database)
+	balanceRepo := repositories.NewBalanceRepository(database)
+	approvalRepo := repositories.NewApprovalRepository(database)
+	attachmentRepo := repositories.NewAttachmentRepository(database)
+	calendarRepo := repositories.NewCalendarRepository(database)
+	gatePassRepo := repositories.NewGatePassRepository(database)
+	delegationRepo := repositories.NewDelegationRepository(database)
+	policyRepo := repositories.NewPolicyRepository(database)
+	leaveTypeRepo := repositories.NewLeaveTypeRepository(database)
+	commentRepo := repositories.NewCommentRepository(database)
+	leaveEventRepo := repositories.NewLeaveEventRepository(database)
+	consentRepo := repositories.NewConsentRepository(database)
+	invitationRepo := repositories.NewInvitationRepository(database)
+	tokenRepo := repositories.NewTokenRepository(database)
+	mfaRepo := repositories.NewMFARepository(database)
+	loginThrottleRepo := repositories.NewLoginThrottleRepository(database)
+
+	blobStore, err := storage.New(cfg.Storage)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
 
 	notificationService := services.NewNotificationService(cfg.SMTP)
-	userService := services.NewUserService(userRepo)
-	leaveService := services.NewLeaveService(leaveRepo, attendanceRepo, notificationService)
-	attendanceService := services.NewAttendanceService(attendanceRepo)
+	invitationService := services.NewInvitationService(
+		invitationRepo,
+		userRepo,
+		notificationService,
+		invitationSigner,
+		cfg.Invitation,
+		cfg.Server.BaseURL,
+	)
+	loginThrottleService := services.NewLoginThrottleService(loginThrottleRepo, notificationService, cfg.Login)
+	userService := services.NewUserService(userRepo, consentRepo, invitationService, loginThrottleService)
+	tokenService := services.NewTokenService(tokenRepo, userRepo, jwtService, cfg.JWT.RefreshExpiry)
+	accountService := services.NewAccountService(
+		tokenRepo,
+		userRepo,
+		tokenService,
+		notificationService,
+		cfg.Account,
+		cfg.Server.BaseURL,
+	)
+	mfaService := services.NewMFAService(mfaRepo, tokenRepo, userRepo, tokenService, mfaCipher, cfg.MFA)
+	if cfg.Invitation.BootstrapAdminEmail != "" {
+		if err := userService.BootstrapAdmin(cfg.Invitation.BootstrapAdminEmail, cfg.Invitation.BootstrapAdminPassword); err != nil {
+			log.Fatalf("Failed to create the bootstrap admin: %v", err)
+		}
+	}
+	calendarService := services.NewCalendarService(calendarRepo)
+	gatePassService := services.NewGatePassService(gatePassRepo, userRepo, gatePassSigner)
+	balanceService := services.NewBalanceService(balanceRepo, userRepo, leaveTypeRepo, calendarService)
+	workflowService := services.NewWorkflowService(approvalRepo)
+	leaveTypeService := services.NewLeaveTypeService(leaveTypeRepo, workflowService)
+	delegationService := services.NewDelegationService(delegationRepo, userRepo)
+	policyService := services.NewPolicyService(policyRepo, leaveTypeRepo, calendarService, cfg.Policy, cfg.Attachment.RequiredOverDays)
+	attachmentService := services.NewAttachmentService(
+		attachmentRepo,
+		leaveRepo,
+		userRepo,
+		delegationService,
+		policyService,
+		blobStore,
+		cfg.Attachment,
+	)
+	consentService := services.NewConsentService(
+		consentRepo,
+		leaveRepo,
+		notificationService,
+		consentSigner,
+		cfg.Consent,
+		cfg.Server.BaseURL,
+	)
+	leaveService := services.NewLeaveService(
+		leaveRepo,
+		leaveEventRepo,
+		attendanceRepo,
+		userRepo,
+		notificationService,
+		balanceService,
+		workflowService,
+		attachmentService,
+		calendarService,
+		gatePassService,
+		delegationService,
+		policyService,
+		leaveTypeService,
+		consentService,
+		cfg.Timetable,
+		cfg.SLA,
+	)
+	if cfg.Policy.File != "" {
+		if err := policyService.LoadFile(cfg.Policy.File); err != nil {
+			log.Fatalf("Failed to load leave policies: %v", err)
+		}
+	}
+
+	commentService := services.NewCommentService(commentRepo, leaveService, notificationService)
+	escalationService := services.NewEscalationService(leaveService, userRepo, notificationService, cfg.SLA)
+	attendanceService := services.NewAttendanceService(attendanceRepo, calendarService, cfg.Timetable)
 
-	authHandler := handlers.NewAuthHandler(userService, jwtService)
+	authHandler := handlers.NewAuthHandler(userService, tokenService, accountService, mfaService)
+	mfaHandler := handlers.NewMFAHandler(mfaService)
 	userHandler := handlers.NewUserHandler(userService)
 	leaveHandler := handlers.NewLeaveHandler(leaveService)
+	balanceHandler := handlers.NewBalanceHandler(balanceService)
+	leaveTypeHandler := handlers.NewLeaveTypeHandler(leaveTypeService)
+	workflowHandler := handlers.NewWorkflowHandler(workflowService, leaveTypeService)
+	policyHandler := handlers.NewPolicyHandler(policyService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService, cfg.Attachment.MaxSize)
+	commentHandler := handlers.NewCommentHandler(commentService)
+	consentHandler := handlers.NewConsentHandler(consentService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	gatePassHandler := handlers.NewGatePassHandler(gatePassService)
 	attendanceHandler := handlers.NewAttendanceHandler(attendanceService)
+	delegationHandler := handlers.NewDelegationHandler(delegationService)
 	analyticsHandler := handlers.NewAnalyticsHandler(leaveService, attendanceService)
 
 	router := routes.NewRouter(
 		authHandler,
+		mfaHandler,
 		userHandler,
 		leaveHandler,
+		balanceHandler,
+		leaveTypeHandler,
+		workflowHandler,
+		policyHandler,
+		attachmentHandler,
+		commentHandler,
+		consentHandler,
+		invitationHandler,
 		attendanceHandler,
+		calendarHandler,
+		gatePassHandler,
+		delegationHandler,
 		analyticsHandler,
 		jwtService,
+		tokenService,
+		mfaService,
+		delegationService,
 	)
 
 	engine := router.Setup()
 
+	escalationService.Start(context.Background())
+
 	addr := cfg.Server.Host + ":" + cfg.Server.Port
 	log.Printf("Server starting on %s", addr)
 	if err := engine.Run(addr); err != nil {
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/api/middleware"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/services"
+)
+
+type AttachmentHandler struct {
+	service *services.AttachmentService
+	maxSize int64
+}
+
+func NewAttachmentHandler(service *services.AttachmentService, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{
+		service: service,
+		maxSize: maxSize,
+	}
+}
+
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	// Leave headroom for the multipart envelope around the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, "Multipart field \"file\" is required")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.service.Upload(uint(leaveID), userID, fileHeader.Filename, file)
+	if err != nil {
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusCreated, "Attachment uploaded successfully", attachment)
+}
+
+func (h *AttachmentHandler) List(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	attachments, err := h.service.List(uint(leaveID), userID)
+	if err != nil {
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Attachments retrieved successfully", attachments)
+}
+
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	attachmentID, err := strconv.ParseUint(c.Param("attachment_id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	attachment, content, err := h.service.Open(uint(leaveID), uint(attachmentID), userID)
+	if err != nil {
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+	})
+}
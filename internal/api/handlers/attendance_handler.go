@@ -39,7 +39,7 @@ func (h *AttendanceHandler) MarkAttendance(c *gin.Context) {
 		return
 	}
 
-	err = h.service.MarkAttendance(req.StudentID, date, req.Present, markerID)
+	err = h.service.MarkAttendance(req.StudentID, date, req.Period, req.Present, markerID)
 	if err != nil {
 		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
 		return
@@ -1,24 +1,35 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/prannvs/campus-leave-system/internal/auth"
+	"github.com/prannvs/campus-leave-system/internal/api/middleware"
 	"github.com/prannvs/campus-leave-system/internal/core"
 	"github.com/prannvs/campus-leave-system/internal/models"
 	"github.com/prannvs/campus-leave-system/internal/services"
 )
 
 type AuthHandler struct {
-	userService *services.UserService
-	jwtService  *auth.JWTService
+	userService    *services.UserService
+	tokenService   *services.TokenService
+	accountService *services.AccountService
+	mfaService     *services.MFAService
 }
 
-func NewAuthHandler(userService *services.UserService, jwtService *auth.JWTService) *AuthHandler {
+func NewAuthHandler(
+	userService *services.UserService,
+	tokenService *services.TokenService,
+	accountService *services.AccountService,
+	mfaService *services.MFAService,
+) *AuthHandler {
 	return &AuthHandler{
-		userService: userService,
-		jwtService:  jwtService,
+		userService:    userService,
+		tokenService:   tokenService,
+		accountService: accountService,
+		mfaService:     mfaService,
 	}
 }
 
@@ -31,19 +42,34 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	user, err := h.userService.Register(req)
 	if err != nil {
-		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		core.ErrorResponse(c, invitationErrorStatus(err), err, nil)
+		return
+	}
+
+	if !user.IsEmailVerified() {
+		if err := h.accountService.SendVerification(user); err != nil {
+			core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+			return
+		}
+	}
+	if err := h.accountService.CheckVerified(user); err != nil {
+		core.SuccessResponse(c, http.StatusCreated, "User registered successfully; confirm your email address to sign in", gin.H{
+			"user": user,
+		})
 		return
 	}
 
-	token, err := h.jwtService.GenerateToken(user)
+	tokens, err := h.tokenService.Issue(user, false)
 	if err != nil {
 		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
 		return
 	}
 
 	core.SuccessResponse(c, http.StatusCreated, "User registered successfully", gin.H{
-		"user":  user,
-		"token": token,
+		"user":          user,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
 	})
 }
 
@@ -54,20 +80,168 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.Login(req.Email, req.Password)
+	user, err := h.userService.Login(req.Email, req.Password, c.ClientIP())
 	if err != nil {
-		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		var throttled *models.LoginThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", strconv.Itoa(int(throttled.RetryAfter.Seconds())+1))
+			core.ErrorResponse(c, http.StatusTooManyRequests, err, nil)
+		case errors.Is(err, models.ErrInvalidCredentials):
+			core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		default:
+			core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+		}
 		return
 	}
 
-	token, err := h.jwtService.GenerateToken(user)
+	if err := h.accountService.CheckVerified(user); err != nil {
+		core.ErrorResponse(c, http.StatusForbidden, err, nil)
+		return
+	}
+
+	if user.IsMFAEnabled() {
+		challenge, err := h.mfaService.Challenge(user)
+		if err != nil {
+			core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+			return
+		}
+		core.SuccessResponse(c, http.StatusOK, "Enter the code from your authenticator app", gin.H{
+			"mfa_required":    true,
+			"challenge_token": challenge.ChallengeToken,
+			"expires_in":      challenge.ExpiresIn,
+		})
+		return
+	}
+
+	tokens, err := h.tokenService.Issue(user, false)
 	if err != nil {
 		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
 		return
 	}
 
 	core.SuccessResponse(c, http.StatusOK, "Login successful", gin.H{
-		"user":  user,
-		"token": token,
+		"user":          user,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		// the account can only reach the two-factor setup routes until it enrols
+		"mfa_setup_required": h.mfaService.Required(user.Role),
 	})
 }
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	tokens, err := h.tokenService.Refresh(req.RefreshToken)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrInvalidRefreshToken) || errors.Is(err, models.ErrRefreshTokenReused) {
+			status = http.StatusUnauthorized
+		}
+		core.ErrorResponse(c, status, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Token refreshed successfully", tokens)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	var req models.LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+			return
+		}
+	}
+
+	jti, expiresAt := middleware.GetAccessToken(c)
+	if err := h.tokenService.Logout(userID, jti, expiresAt, req.RefreshToken); err != nil {
+		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Logged out successfully", nil)
+}
+
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req models.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	user, err := h.accountService.VerifyEmail(req.Token)
+	if err != nil {
+		core.ErrorResponse(c, accountErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Email verified successfully", user)
+}
+
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req models.EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	if err := h.accountService.ResendVerification(req.Email); err != nil {
+		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "If the address belongs to an unverified account, a new link is on its way", nil)
+}
+
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req models.EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	if err := h.accountService.ForgotPassword(req.Email); err != nil {
+		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "If the address belongs to an account, a reset link is on its way", nil)
+}
+
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	if err := h.accountService.ResetPassword(req.Token, req.Password); err != nil {
+		core.ErrorResponse(c, accountErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Password reset successfully", nil)
+}
+
+func accountErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidAccountToken):
+		return http.StatusBadRequest
+	case errors.Is(err, models.ErrEmailNotVerified):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/api/middleware"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/services"
+)
+
+type BalanceHandler struct {
+	service *services.BalanceService
+}
+
+func NewBalanceHandler(service *services.BalanceService) *BalanceHandler {
+	return &BalanceHandler{service: service}
+}
+
+func (h *BalanceHandler) GetBalance(c *gin.Context) {
+	studentID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	// Staff may look up any student's balance
+	if studentIDParam := c.Query("student_id"); studentIDParam != "" {
+		if role, _ := middleware.GetUserRole(c); role == models.RoleStudent {
+			core.ErrorResponse(c, http.StatusForbidden, models.ErrInvalidRole, "Students can only view their own balance")
+			return
+		}
+		id, err := strconv.ParseUint(studentIDParam, 10, 32)
+		if err != nil {
+			core.ErrorResponse(c, http.StatusBadRequest, err, "Invalid student ID")
+			return
+		}
+		studentID = uint(id)
+	}
+
+	balances, err := h.service.GetBalances(studentID)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusNotFound, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave balance retrieved successfully", balances)
+}
+
+func (h *BalanceHandler) GetQuotas(c *gin.Context) {
+	quotas, err := h.service.GetQuotas()
+	if err != nil {
+		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave quotas retrieved successfully", quotas)
+}
+
+func (h *BalanceHandler) CreateQuota(c *gin.Context) {
+	var req models.CreateQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	quota, err := h.service.CreateQuota(req)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusCreated, "Leave quota created successfully", quota)
+}
+
+func (h *BalanceHandler) DeleteQuota(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	if err := h.service.DeleteQuota(uint(id)); err != nil {
+		core.ErrorResponse(c, http.StatusNotFound, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave quota deleted successfully", nil)
+}
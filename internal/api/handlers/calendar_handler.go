@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/services"
+)
+
+type CalendarHandler struct {
+	service *services.CalendarService
+}
+
+func NewCalendarHandler(service *services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{service: service}
+}
+
+func (h *CalendarHandler) GetCalendar(c *gin.Context) {
+	calendar, err := h.service.GetCalendar()
+	if err != nil {
+		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Academic calendar retrieved successfully", calendar)
+}
+
+func (h *CalendarHandler) CreateTerm(c *gin.Context) {
+	var req models.CreateTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	term, err := h.service.CreateTerm(req)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusCreated, "Academic term created successfully", term)
+}
+
+func (h *CalendarHandler) DeleteTerm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	if err := h.service.DeleteTerm(uint(id)); err != nil {
+		core.ErrorResponse(c, http.StatusNotFound, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Academic term deleted successfully", nil)
+}
+
+func (h *CalendarHandler) CreateEvent(c *gin.Context) {
+	var req models.CreateCalendarEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	event, err := h.service.CreateEvent(req)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusCreated, "Calendar event created successfully", event)
+}
+
+func (h *CalendarHandler) DeleteEvent(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	if err := h.service.DeleteEvent(uint(id)); err != nil {
+		core.ErrorResponse(c, http.StatusNotFound, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Calendar event deleted successfully", nil)
+}
+
+func (h *CalendarHandler) SetWeeklyOff(c *gin.Context) {
+	var req models.SetWeeklyOffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	weekdays, err := h.service.SetWeeklyOff(req)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Weekly offs updated successfully", weekdays)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/api/middleware"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/services"
+)
+
+type CommentHandler struct {
+	service *services.CommentService
+}
+
+func NewCommentHandler(service *services.CommentService) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+func (h *CommentHandler) List(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	comments, err := h.service.List(uint(leaveID), userID)
+	if err != nil {
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Comments retrieved successfully", comments)
+}
+
+func (h *CommentHandler) Add(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	var req models.AddCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	comment, err := h.service.Add(uint(leaveID), userID, req)
+	if err != nil {
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusCreated, "Comment added successfully", comment)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/api/middleware"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/services"
+)
+
+type ConsentHandler struct {
+	service *services.ConsentService
+}
+
+func NewConsentHandler(service *services.ConsentService) *ConsentHandler {
+	return &ConsentHandler{service: service}
+}
+
+// public: the guardian opens the emailed link
+func (h *ConsentHandler) View(c *gin.Context) {
+	consent, err := h.service.View(c.Param("token"))
+	if err != nil {
+		core.ErrorResponse(c, consentErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Consent request retrieved successfully", consent)
+}
+
+// public: the guardian approves or declines through the emailed link
+func (h *ConsentHandler) Respond(c *gin.Context) {
+	var req models.ConsentDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	consent, err := h.service.Respond(c.Param("token"), req)
+	if err != nil {
+		core.ErrorResponse(c, consentErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Consent recorded successfully", consent)
+}
+
+func (h *ConsentHandler) Resend(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	if err := h.service.Resend(uint(leaveID), userID); err != nil {
+		core.ErrorResponse(c, consentErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Consent request sent successfully", nil)
+}
+
+func consentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrLeaveNotFound), errors.Is(err, models.ErrConsentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvalidConsentLink):
+		return http.StatusGone
+	case errors.Is(err, models.ErrLeaveNotPending):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/api/middleware"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/services"
+)
+
+type DelegationHandler struct {
+	service *services.DelegationService
+}
+
+func NewDelegationHandler(service *services.DelegationService) *DelegationHandler {
+	return &DelegationHandler{service: service}
+}
+
+func (h *DelegationHandler) CreateDelegation(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	var req models.CreateDelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	delegation, err := h.service.Create(userID, req)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, models.ErrUserNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, models.ErrInvalidRole):
+			status = http.StatusForbidden
+		}
+		core.ErrorResponse(c, status, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusCreated, "Delegation created successfully", delegation)
+}
+
+func (h *DelegationHandler) GetDelegations(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	delegations, err := h.service.List(userID)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Delegations retrieved successfully", delegations)
+}
+
+func (h *DelegationHandler) RevokeDelegation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	if err := h.service.Revoke(uint(id), userID); err != nil {
+		core.ErrorResponse(c, http.StatusNotFound, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Delegation revoked successfully", nil)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/api/middleware"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/services"
+)
+
+type GatePassHandler struct {
+	service *services.GatePassService
+}
+
+func NewGatePassHandler(service *services.GatePassService) *GatePassHandler {
+	return &GatePassHandler{service: service}
+}
+
+func (h *GatePassHandler) GetForLeave(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	pass, err := h.service.GetForLeave(uint(leaveID), userID)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusNotFound, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Gate pass retrieved successfully", pass)
+}
+
+func (h *GatePassHandler) CheckOut(c *gin.Context) {
+	var req models.GatePassScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	guardID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	pass, err := h.service.CheckOut(req.Code, guardID)
+	if err != nil {
+		core.ErrorResponse(c, gatePassErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Student checked out successfully", pass)
+}
+
+func (h *GatePassHandler) CheckIn(c *gin.Context) {
+	var req models.GatePassScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	guardID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	pass, err := h.service.CheckIn(req.Code, guardID)
+	if err != nil {
+		core.ErrorResponse(c, gatePassErrorStatus(err), err, nil)
+		return
+	}
+
+	message := "Student checked in successfully"
+	if pass.LateReturn {
+		message = "Student checked in after the leave ended"
+	}
+	core.SuccessResponse(c, http.StatusOK, message, pass)
+}
+
+func (h *GatePassHandler) GetCurrentlyOut(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	passes, err := h.service.CurrentlyOut(userID, c.Query("hostel"))
+	if err != nil {
+		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Students currently out retrieved successfully", passes)
+}
+
+func gatePassErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidGatePass):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrGatePassNotUsable), errors.Is(err, models.ErrGatePassNotValidNow):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
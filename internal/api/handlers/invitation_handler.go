@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/api/middleware"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/services"
+)
+
+type InvitationHandler struct {
+	service *services.InvitationService
+}
+
+func NewInvitationHandler(service *services.InvitationService) *InvitationHandler {
+	return &InvitationHandler{service: service}
+}
+
+func (h *InvitationHandler) CreateInvitation(c *gin.Context) {
+	var req models.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	invitation, err := h.service.Create(userID, req)
+	if err != nil {
+		core.ErrorResponse(c, invitationErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusCreated, "Invitation created successfully", invitation)
+}
+
+func (h *InvitationHandler) GetInvitations(c *gin.Context) {
+	invitations, err := h.service.List()
+	if err != nil {
+		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Invitations retrieved successfully", invitations)
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	if err := h.service.Revoke(uint(id)); err != nil {
+		core.ErrorResponse(c, invitationErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Invitation revoked successfully", nil)
+}
+
+func invitationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvitationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrInvitationRequired):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrEmailTaken), errors.Is(err, models.ErrInvalidInvitation):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
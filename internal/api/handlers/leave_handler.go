@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -34,6 +35,10 @@ func (h *LeaveHandler) ApplyLeave(c *gin.Context) {
 
 	leave, err := h.service.ApplyLeave(userID, req)
 	if err != nil {
+		if details := leaveErrorDetails(err); details != nil {
+			core.ErrorResponse(c, http.StatusUnprocessableEntity, err, details)
+			return
+		}
 		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
 		return
 	}
@@ -41,6 +46,191 @@ func (h *LeaveHandler) ApplyLeave(c *gin.Context) {
 	core.SuccessResponse(c, http.StatusCreated, "Leave request submitted successfully", leave)
 }
 
+func (h *LeaveHandler) UpdateLeave(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	var req models.ApplyLeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	leave, err := h.service.UpdateLeave(uint(leaveID), userID, req)
+	if err != nil {
+		if details := leaveErrorDetails(err); details != nil {
+			core.ErrorResponse(c, http.StatusUnprocessableEntity, err, details)
+			return
+		}
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave request updated successfully", leave)
+}
+
+func (h *LeaveHandler) ExtendLeave(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	var req models.ExtendLeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	extension, err := h.service.ExtendLeave(uint(leaveID), userID, req)
+	if err != nil {
+		if details := leaveErrorDetails(err); details != nil {
+			core.ErrorResponse(c, http.StatusUnprocessableEntity, err, details)
+			return
+		}
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusCreated, "Leave extension submitted successfully", extension)
+}
+
+func (h *LeaveHandler) WithdrawLeave(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	if err := h.service.WithdrawLeave(uint(leaveID), userID); err != nil {
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave request withdrawn successfully", nil)
+}
+
+func (h *LeaveHandler) CancelLeave(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	if err := h.service.CancelLeave(uint(leaveID), userID); err != nil {
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave cancelled successfully", nil)
+}
+
+func (h *LeaveHandler) CancelOccurrence(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	occurrenceID, err := strconv.ParseUint(c.Param("occurrence_id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	if err := h.service.CancelOccurrence(uint(leaveID), uint(occurrenceID), userID); err != nil {
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Occurrence cancelled successfully", nil)
+}
+
+func (h *LeaveHandler) CancelRemaining(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	if err := h.service.CancelRemaining(uint(leaveID), userID); err != nil {
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Remaining occurrences cancelled successfully", nil)
+}
+
+func (h *LeaveHandler) SearchLeaves(c *gin.Context) {
+	var req models.SearchLeavesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	page, pageSize := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	leaves, total, err := h.service.Search(userID, req, page, pageSize)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	pagination := core.CreatePaginationResponse(page, pageSize, total, leaves)
+	core.SuccessResponse(c, http.StatusOK, "Leaves retrieved successfully", pagination)
+}
+
 func (h *LeaveHandler) GetMyLeaves(c *gin.Context) {
 	userID, err := middleware.GetUserID(c)
 	if err != nil {
@@ -58,7 +248,13 @@ func (h *LeaveHandler) GetMyLeaves(c *gin.Context) {
 }
 
 func (h *LeaveHandler) GetPendingLeaves(c *gin.Context) {
-	leaves, err := h.service.GetPendingLeaves()
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	leaves, err := h.service.GetPendingLeaves(userID)
 	if err != nil {
 		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
 		return
@@ -89,13 +285,35 @@ func (h *LeaveHandler) ApproveLeave(c *gin.Context) {
 	status := models.LeaveStatus(req.Status)
 	err = h.service.ApproveLeave(uint(leaveID), approverID, status, req.Remarks)
 	if err != nil {
-		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		core.ErrorResponse(c, leaveErrorStatus(err), err, leaveErrorDetails(err))
 		return
 	}
 
 	core.SuccessResponse(c, http.StatusOK, "Leave "+req.Status+" successfully", nil)
 }
 
+func (h *LeaveHandler) BulkDecision(c *gin.Context) {
+	var req models.BulkDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	approverID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	results, err := h.service.BulkDecide(approverID, req)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Bulk decision processed", results)
+}
+
 func (h *LeaveHandler) DeleteLeave(c *gin.Context) {
 	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -103,10 +321,108 @@ func (h *LeaveHandler) DeleteLeave(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Delete(uint(leaveID)); err != nil {
-		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	if err := h.service.Delete(uint(leaveID), userID); err != nil {
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
 		return
 	}
 
 	core.SuccessResponse(c, http.StatusOK, "Leave deleted successfully", nil)
 }
+
+func (h *LeaveHandler) GetConsent(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	consent, err := h.service.GetConsent(uint(leaveID), userID)
+	if err != nil {
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Consent retrieved successfully", consent)
+}
+
+func (h *LeaveHandler) GetHistory(c *gin.Context) {
+	leaveID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	events, err := h.service.History(uint(leaveID), userID)
+	if err != nil {
+		core.ErrorResponse(c, leaveErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave history retrieved successfully", events)
+}
+
+// structured details for errors that carry more than a message
+func leaveErrorDetails(err error) interface{} {
+	var balanceErr *models.InsufficientBalanceError
+	if errors.As(err, &balanceErr) {
+		return balanceErr
+	}
+
+	var policyErr *models.PolicyViolationError
+	if errors.As(err, &policyErr) {
+		return policyErr.Violations
+	}
+	return nil
+}
+
+// maps leave service errors onto HTTP status codes
+func leaveErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrLeaveNotFound),
+		errors.Is(err, models.ErrAttachmentNotFound),
+		errors.Is(err, models.ErrOccurrenceNotFound),
+		errors.Is(err, models.ErrConsentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrNotAwaitingApprover), errors.Is(err, models.ErrOutOfScope):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrAttachmentTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, models.ErrUnsupportedFileType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, models.ErrAttachmentRequired),
+		errors.Is(err, models.ErrGuardianRequired),
+		errors.Is(err, models.ErrConsentPending),
+		errors.Is(err, models.ErrConsentDeclined):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, models.ErrLeaveNotPending),
+		errors.Is(err, models.ErrLeaveNotEditable),
+		errors.Is(err, models.ErrLeaveNotCancellable),
+		errors.Is(err, models.ErrLeaveAlreadyStarted),
+		errors.Is(err, models.ErrLeaveNotExtendable),
+		errors.Is(err, models.ErrExtensionPending),
+		errors.Is(err, models.ErrExtensionNotEditable),
+		errors.Is(err, models.ErrInvalidTransition),
+		errors.Is(err, models.ErrNotRecurring):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
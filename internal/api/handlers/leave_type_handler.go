@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/api/middleware"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/services"
+)
+
+type LeaveTypeHandler struct {
+	service *services.LeaveTypeService
+}
+
+func NewLeaveTypeHandler(service *services.LeaveTypeService) *LeaveTypeHandler {
+	return &LeaveTypeHandler{service: service}
+}
+
+// everyone sees the active catalogue, admins can ask for deactivated types too
+func (h *LeaveTypeHandler) GetLeaveTypes(c *gin.Context) {
+	includeInactive := middleware.IsAdmin(c) && c.Query("include_inactive") == "true"
+
+	leaveTypes, err := h.service.List(includeInactive)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave types retrieved successfully", leaveTypes)
+}
+
+func (h *LeaveTypeHandler) GetLeaveType(c *gin.Context) {
+	leaveType, err := h.service.Get(models.LeaveType(c.Param("code")))
+	if err != nil {
+		core.ErrorResponse(c, leaveTypeErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave type retrieved successfully", leaveType)
+}
+
+func (h *LeaveTypeHandler) CreateLeaveType(c *gin.Context) {
+	var req models.CreateLeaveTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	leaveType, err := h.service.Create(req)
+	if err != nil {
+		core.ErrorResponse(c, leaveTypeErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusCreated, "Leave type created successfully", leaveType)
+}
+
+func (h *LeaveTypeHandler) UpdateLeaveType(c *gin.Context) {
+	var req models.UpdateLeaveTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	leaveType, err := h.service.Update(models.LeaveType(c.Param("code")), req)
+	if err != nil {
+		core.ErrorResponse(c, leaveTypeErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave type updated successfully", leaveType)
+}
+
+func (h *LeaveTypeHandler) DeleteLeaveType(c *gin.Context) {
+	if err := h.service.Delete(models.LeaveType(c.Param("code"))); err != nil {
+		core.ErrorResponse(c, leaveTypeErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave type deleted successfully", nil)
+}
+
+func leaveTypeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrLeaveTypeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrLeaveTypeExists), errors.Is(err, models.ErrLeaveTypeInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
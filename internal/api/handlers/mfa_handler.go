@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/api/middleware"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/services"
+)
+
+type MFAHandler struct {
+	service *services.MFAService
+}
+
+func NewMFAHandler(service *services.MFAService) *MFAHandler {
+	return &MFAHandler{service: service}
+}
+
+func (h *MFAHandler) Setup(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	setup, err := h.service.Setup(userID)
+	if err != nil {
+		core.ErrorResponse(c, mfaErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Add the secret to your authenticator app and confirm a code", setup)
+}
+
+func (h *MFAHandler) Enable(c *gin.Context) {
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	enabled, err := h.service.Enable(userID, req.Code)
+	if err != nil {
+		core.ErrorResponse(c, mfaErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Two-factor authentication enabled; store the recovery codes safely", enabled)
+}
+
+func (h *MFAHandler) Disable(c *gin.Context) {
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	if err := h.service.Disable(userID, req.Code); err != nil {
+		core.ErrorResponse(c, mfaErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(userID, req.Code)
+	if err != nil {
+		core.ErrorResponse(c, mfaErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Recovery codes replaced", gin.H{"recovery_codes": codes})
+}
+
+// second step of a login for accounts with two-factor on
+func (h *MFAHandler) Verify(c *gin.Context) {
+	var req models.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	user, tokens, err := h.service.Verify(req.ChallengeToken, req.Code)
+	if err != nil {
+		core.ErrorResponse(c, mfaErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Login successful", gin.H{
+		"user":          user,
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+func (h *MFAHandler) Reset(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	if err := h.service.Reset(uint(id)); err != nil {
+		core.ErrorResponse(c, mfaErrorStatus(err), err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Two-factor authentication reset", nil)
+}
+
+func mfaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, models.ErrInvalidMFACode), errors.Is(err, models.ErrInvalidAccountToken):
+		return http.StatusUnauthorized
+	case errors.Is(err, models.ErrMFAAlreadyEnabled), errors.Is(err, models.ErrMFANotEnrolled):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrMFAEnforced):
+		return http.StatusForbidden
+	case errors.Is(err, models.ErrUserNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
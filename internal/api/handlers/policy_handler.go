@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/services"
+)
+
+type PolicyHandler struct {
+	service *services.PolicyService
+}
+
+func NewPolicyHandler(service *services.PolicyService) *PolicyHandler {
+	return &PolicyHandler{service: service}
+}
+
+func (h *PolicyHandler) GetPolicies(c *gin.Context) {
+	policies, err := h.service.GetPolicies()
+	if err != nil {
+		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave policies retrieved successfully", policies)
+}
+
+func (h *PolicyHandler) SavePolicy(c *gin.Context) {
+	var req models.SavePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	policy, err := h.service.SavePolicy(models.LeaveType(c.Param("leave_type")), req)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave policy saved successfully", policy)
+}
+
+func (h *PolicyHandler) DeletePolicy(c *gin.Context) {
+	if err := h.service.DeletePolicy(models.LeaveType(c.Param("leave_type"))); err != nil {
+		core.ErrorResponse(c, http.StatusNotFound, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Leave policy deleted successfully", nil)
+}
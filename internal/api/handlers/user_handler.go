@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/api/middleware"
 	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
 	"github.com/prannvs/campus-leave-system/internal/services"
 )
 
@@ -38,7 +41,13 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.service.GetByID(uint(id))
+	viewerID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	user, err := h.service.View(uint(id), viewerID)
 	if err != nil {
 		core.ErrorResponse(c, http.StatusNotFound, err, nil)
 		return
@@ -47,6 +56,43 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	core.SuccessResponse(c, http.StatusOK, "User retrieved successfully", user)
 }
 
+func (h *UserHandler) UpdateGuardian(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	var req models.GuardianInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusUnauthorized, err, nil)
+		return
+	}
+
+	user, err := h.service.UpdateGuardian(uint(id), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUnauthorized):
+			core.ErrorResponse(c, http.StatusForbidden, err, nil)
+		case errors.Is(err, models.ErrGuardianLocked):
+			core.ErrorResponse(c, http.StatusConflict, err, nil)
+		case errors.Is(err, models.ErrUserNotFound):
+			core.ErrorResponse(c, http.StatusNotFound, err, nil)
+		default:
+			core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		}
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Guardian details updated successfully", user)
+}
+
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -61,3 +107,22 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 
 	core.SuccessResponse(c, http.StatusOK, "User deleted successfully", nil)
 }
+
+func (h *UserHandler) UnlockUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	if err := h.service.Unlock(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, models.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		core.ErrorResponse(c, status, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "User unlocked successfully", nil)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/services"
+)
+
+type WorkflowHandler struct {
+	service          *services.WorkflowService
+	leaveTypeService *services.LeaveTypeService
+}
+
+func NewWorkflowHandler(service *services.WorkflowService, leaveTypeService *services.LeaveTypeService) *WorkflowHandler {
+	return &WorkflowHandler{service: service, leaveTypeService: leaveTypeService}
+}
+
+func (h *WorkflowHandler) GetWorkflows(c *gin.Context) {
+	workflows, err := h.service.GetWorkflows()
+	if err != nil {
+		core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Approval workflows retrieved successfully", gin.H{
+		"workflows": workflows,
+		"default":   models.DefaultApprovalSteps,
+	})
+}
+
+func (h *WorkflowHandler) SaveWorkflow(c *gin.Context) {
+	var req models.SaveWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	// A workflow is only reachable through a leave type in the catalogue
+	leaveType := models.LeaveType(c.Param("leave_type"))
+	if _, err := h.leaveTypeService.Get(leaveType); err != nil {
+		core.ErrorResponse(c, leaveTypeErrorStatus(err), err, nil)
+		return
+	}
+
+	workflow, err := h.service.SaveWorkflow(leaveType, req)
+	if err != nil {
+		core.ErrorResponse(c, http.StatusBadRequest, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Approval workflow saved successfully", workflow)
+}
+
+func (h *WorkflowHandler) DeleteWorkflow(c *gin.Context) {
+	if err := h.service.DeleteWorkflow(models.LeaveType(c.Param("leave_type"))); err != nil {
+		core.ErrorResponse(c, http.StatusNotFound, err, nil)
+		return
+	}
+
+	core.SuccessResponse(c, http.StatusOK, "Approval workflow deleted successfully", nil)
+}
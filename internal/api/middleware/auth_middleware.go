@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prannvs/campus-leave-system/internal/auth"
@@ -10,7 +13,12 @@ import (
 	"github.com/prannvs/campus-leave-system/internal/models"
 )
 
-func AuthMiddleware(jwtService *auth.JWTService) gin.HandlerFunc {
+// decides whether a validly signed access token has since been revoked
+type TokenRevocationChecker interface {
+	CheckAccess(userID uint, jti string) error
+}
+
+func AuthMiddleware(jwtService *auth.JWTService, revocations TokenRevocationChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -36,13 +44,84 @@ func AuthMiddleware(jwtService *auth.JWTService) gin.HandlerFunc {
 			return
 		}
 
+		if err := revocations.CheckAccess(claims.UserID, claims.ID); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, models.ErrTokenRevoked) {
+				status = http.StatusUnauthorized
+			}
+			core.ErrorResponse(c, status, err, nil)
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Set("role", claims.Role)
+		c.Set("mfa", claims.MFA)
+		c.Set("token_id", claims.ID)
+		if claims.ExpiresAt != nil {
+			c.Set("token_expires_at", claims.ExpiresAt.Time)
+		}
 		c.Next()
 	}
 }
 
+// tells which roles must sign in with a second factor
+type MFAPolicy interface {
+	Required(role models.Role) bool
+}
+
+// turns away sessions without a second factor for roles that require one
+func MFAMiddleware(policy MFAPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := GetUserRole(c)
+		if policy.Required(role) && !c.GetBool("mfa") {
+			core.ErrorResponse(c, http.StatusForbidden,
+				models.ErrMFARequired, "Set up two-factor authentication through /api/auth/mfa/setup and sign in again")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// looks up the roles a user is temporarily standing in for
+type DelegatedRoleResolver interface {
+	DelegatedRoles(userID uint) ([]models.Role, error)
+}
+
+// adds the roles of any colleagues the user is covering for to the context; a role that requires a
+// second factor is only lent to sessions that signed in with one
+func DelegationMiddleware(resolver DelegatedRoleResolver, policy MFAPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := GetUserID(c)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		roles, err := resolver.DelegatedRoles(userID)
+		if err != nil {
+			core.ErrorResponse(c, http.StatusInternalServerError, err, nil)
+			c.Abort()
+			return
+		}
+
+		var lent []models.Role
+		for _, role := range roles {
+			if policy.Required(role) && !c.GetBool("mfa") {
+				c.Set("delegation_needs_mfa", true)
+				continue
+			}
+			lent = append(lent, role)
+		}
+
+		c.Set("delegated_roles", lent)
+		c.Next()
+	}
+}
+
+// admits users whose own role is allowed
 func RoleMiddleware(allowedRoles ...models.Role) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
@@ -53,9 +132,43 @@ func RoleMiddleware(allowedRoles ...models.Role) gin.HandlerFunc {
 			return
 		}
 
-		userRole := role.(models.Role)
-		for _, allowedRole := range allowedRoles {
-			if userRole == allowedRole {
+		if !slices.Contains(allowedRoles, role.(models.Role)) {
+			core.ErrorResponse(c, http.StatusForbidden,
+				models.ErrInvalidRole, "Insufficient permissions")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// like RoleMiddleware, but also admits users covering for a colleague with an allowed role;
+// only the leave approval routes lend access this way. While covering for a role that requires a
+// second factor, the session needs one here, since any approval may be made on that colleague's behalf
+func DelegatedRoleMiddleware(allowedRoles ...models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists {
+			core.ErrorResponse(c, http.StatusUnauthorized,
+				models.ErrUnauthorized, "Role not found in context")
+			c.Abort()
+			return
+		}
+
+		if c.GetBool("delegation_needs_mfa") {
+			core.ErrorResponse(c, http.StatusForbidden,
+				models.ErrMFARequired, "Sign in with two-factor authentication to approve while covering for a colleague")
+			c.Abort()
+			return
+		}
+
+		userRoles := []models.Role{role.(models.Role)}
+		if delegated, ok := c.Get("delegated_roles"); ok {
+			userRoles = append(userRoles, delegated.([]models.Role)...)
+		}
+
+		for _, userRole := range userRoles {
+			if slices.Contains(allowedRoles, userRole) {
 				c.Next()
 				return
 			}
@@ -75,6 +188,13 @@ func GetUserID(c *gin.Context) (uint, error) {
 	return userID.(uint), nil
 }
 
+// the jti and expiry of the access token the request was made with
+func GetAccessToken(c *gin.Context) (string, time.Time) {
+	jti := c.GetString("token_id")
+	expiresAt := c.GetTime("token_expires_at")
+	return jti, expiresAt
+}
+
 func GetUserRole(c *gin.Context) (models.Role, error) {
 	role, exists := c.Get("role")
 	if !exists {
@@ -6,32 +6,78 @@ import (
 	"github.com/prannvs/campus-leave-system/internal/api/middleware"
 	"github.com/prannvs/campus-leave-system/internal/auth"
 	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/services"
 )
 
 type Router struct {
 	authHandler       *handlers.AuthHandler
+	mfaHandler        *handlers.MFAHandler
 	userHandler       *handlers.UserHandler
 	leaveHandler      *handlers.LeaveHandler
+	balanceHandler    *handlers.BalanceHandler
+	leaveTypeHandler  *handlers.LeaveTypeHandler
+	workflowHandler   *handlers.WorkflowHandler
+	policyHandler     *handlers.PolicyHandler
+	attachmentHandler *handlers.AttachmentHandler
+	commentHandler    *handlers.CommentHandler
+	consentHandler    *handlers.ConsentHandler
+	invitationHandler *handlers.InvitationHandler
 	attendanceHandler *handlers.AttendanceHandler
+	calendarHandler   *handlers.CalendarHandler
+	gatePassHandler   *handlers.GatePassHandler
+	delegationHandler *handlers.DelegationHandler
 	analyticsHandler  *handlers.AnalyticsHandler
 	jwtService        *auth.JWTService
+	tokenService      *services.TokenService
+	mfaService        *services.MFAService
+	delegationService *services.DelegationService
 }
 
 func NewRouter(
 	authHandler *handlers.AuthHandler,
+	mfaHandler *handlers.MFAHandler,
 	userHandler *handlers.UserHandler,
 	leaveHandler *handlers.LeaveHandler,
+	balanceHandler *handlers.BalanceHandler,
+	leaveTypeHandler *handlers.LeaveTypeHandler,
+	workflowHandler *handlers.WorkflowHandler,
+	policyHandler *handlers.PolicyHandler,
+	attachmentHandler *handlers.AttachmentHandler,
+	commentHandler *handlers.CommentHandler,
+	consentHandler *handlers.ConsentHandler,
+	invitationHandler *handlers.InvitationHandler,
 	attendanceHandler *handlers.AttendanceHandler,
+	calendarHandler *handlers.CalendarHandler,
+	gatePassHandler *handlers.GatePassHandler,
+	delegationHandler *handlers.DelegationHandler,
 	analyticsHandler *handlers.AnalyticsHandler,
 	jwtService *auth.JWTService,
+	tokenService *services.TokenService,
+	mfaService *services.MFAService,
+	delegationService *services.DelegationService,
 ) *Router {
 	return &Router{
 		authHandler:       authHandler,
+		mfaHandler:        mfaHandler,
 		userHandler:       userHandler,
 		leaveHandler:      leaveHandler,
+		balanceHandler:    balanceHandler,
+		leaveTypeHandler:  leaveTypeHandler,
+		workflowHandler:   workflowHandler,
+		policyHandler:     policyHandler,
+		attachmentHandler: attachmentHandler,
+		commentHandler:    commentHandler,
+		consentHandler:    consentHandler,
+		invitationHandler: invitationHandler,
 		attendanceHandler: attendanceHandler,
+		calendarHandler:   calendarHandler,
+		gatePassHandler:   gatePassHandler,
+		delegationHandler: delegationHandler,
 		analyticsHandler:  analyticsHandler,
 		jwtService:        jwtService,
+		tokenService:      tokenService,
+		mfaService:        mfaService,
+		delegationService: delegationService,
 	}
 }
 
@@ -65,39 +111,143 @@ func (r *Router) Setup() *gin.Engine {
 		{
 			auth.POST("/register", r.authHandler.Register)
 			auth.POST("/login", r.authHandler.Login)
+			auth.POST("/refresh", r.authHandler.Refresh)
+			auth.POST("/verify-email", r.authHandler.VerifyEmail)
+			auth.POST("/resend-verification", r.authHandler.ResendVerification)
+			auth.POST("/forgot-password", r.authHandler.ForgotPassword)
+			auth.POST("/reset-password", r.authHandler.ResetPassword)
+			auth.POST("/mfa/verify", r.mfaHandler.Verify)
+		}
+
+		// Signed-in account routes, reachable before a required second factor is set up
+		account := api.Group("/auth")
+		account.Use(middleware.AuthMiddleware(r.jwtService, r.tokenService))
+		{
+			account.POST("/logout", r.authHandler.Logout)
+			account.POST("/mfa/setup", r.mfaHandler.Setup)
+			account.POST("/mfa/enable", r.mfaHandler.Enable)
+			account.POST("/mfa/disable", r.mfaHandler.Disable)
+			account.POST("/mfa/recovery-codes", r.mfaHandler.RegenerateRecoveryCodes)
+		}
+
+		// Guardian consent links, authorised by the signed token itself
+		consent := api.Group("/consent")
+		{
+			consent.GET("/:token", r.consentHandler.View)
+			consent.POST("/:token", r.consentHandler.Respond)
 		}
 
 		// Protected routes
 		protected := api.Group("")
-		protected.Use(middleware.AuthMiddleware(r.jwtService))
+		protected.Use(
+			middleware.AuthMiddleware(r.jwtService, r.tokenService),
+			middleware.MFAMiddleware(r.mfaService),
+			middleware.DelegationMiddleware(r.delegationService, r.mfaService),
+		)
 		{
 			// User routes
 			users := protected.Group("/users")
 			{
 				users.GET("", middleware.RoleMiddleware(models.RoleAdmin), r.userHandler.GetUsers)
 				users.GET("/:id", r.userHandler.GetUser)
+				users.PUT("/:id/guardian",
+					middleware.RoleMiddleware(models.RoleAdmin, models.RoleWarden),
+					r.userHandler.UpdateGuardian)
 				users.DELETE("/:id", middleware.RoleMiddleware(models.RoleAdmin), r.userHandler.DeleteUser)
+				users.DELETE("/:id/mfa", middleware.RoleMiddleware(models.RoleAdmin), r.mfaHandler.Reset)
+				users.POST("/:id/unlock", middleware.RoleMiddleware(models.RoleAdmin), r.userHandler.UnlockUser)
 			}
 
 			// Leave routes
 			leaves := protected.Group("/leaves")
 			{
+				leaves.GET("",
+					middleware.RoleMiddleware(models.RoleStudent, models.RoleFaculty, models.RoleWarden, models.RoleAdmin),
+					r.leaveHandler.SearchLeaves)
+
 				// Student routes
 				leaves.POST("/apply", middleware.RoleMiddleware(models.RoleStudent), r.leaveHandler.ApplyLeave)
 				leaves.GET("/my", middleware.RoleMiddleware(models.RoleStudent), r.leaveHandler.GetMyLeaves)
+				leaves.GET("/balance", r.balanceHandler.GetBalance)
+				leaves.PUT("/:id", middleware.RoleMiddleware(models.RoleStudent), r.leaveHandler.UpdateLeave)
+				leaves.POST("/:id/extend", middleware.RoleMiddleware(models.RoleStudent), r.leaveHandler.ExtendLeave)
+				leaves.POST("/:id/attachments", middleware.RoleMiddleware(models.RoleStudent), r.attachmentHandler.Upload)
+				leaves.GET("/:id/attachments", r.attachmentHandler.List)
+				leaves.GET("/:id/attachments/:attachment_id", r.attachmentHandler.Download)
+				leaves.GET("/:id/history", r.leaveHandler.GetHistory)
+				leaves.POST("/:id/consent/resend", middleware.RoleMiddleware(models.RoleStudent), r.consentHandler.Resend)
+				leaves.GET("/:id/consent", r.leaveHandler.GetConsent)
+				leaves.GET("/:id/comments", r.commentHandler.List)
+				leaves.POST("/:id/comments", r.commentHandler.Add)
+				leaves.GET("/:id/gate-pass", middleware.RoleMiddleware(models.RoleStudent), r.gatePassHandler.GetForLeave)
+				leaves.POST("/:id/withdraw", middleware.RoleMiddleware(models.RoleStudent), r.leaveHandler.WithdrawLeave)
+				leaves.POST("/:id/cancel", middleware.RoleMiddleware(models.RoleStudent), r.leaveHandler.CancelLeave)
+				leaves.POST("/:id/cancel-remaining", middleware.RoleMiddleware(models.RoleStudent), r.leaveHandler.CancelRemaining)
+				leaves.POST("/:id/occurrences/:occurrence_id/cancel",
+					middleware.RoleMiddleware(models.RoleStudent),
+					r.leaveHandler.CancelOccurrence)
 
 				// Faculty/Warden routes
 				leaves.GET("/pending",
-					middleware.RoleMiddleware(models.RoleFaculty, models.RoleWarden, models.RoleAdmin),
+					middleware.DelegatedRoleMiddleware(models.RoleFaculty, models.RoleWarden, models.RoleAdmin),
 					r.leaveHandler.GetPendingLeaves)
 				leaves.PUT("/:id/approve",
-					middleware.RoleMiddleware(models.RoleFaculty, models.RoleWarden, models.RoleAdmin),
+					middleware.DelegatedRoleMiddleware(models.RoleFaculty, models.RoleWarden, models.RoleAdmin),
 					r.leaveHandler.ApproveLeave)
+				leaves.POST("/bulk-decision",
+					middleware.DelegatedRoleMiddleware(models.RoleFaculty, models.RoleWarden, models.RoleAdmin),
+					r.leaveHandler.BulkDecision)
 
 				// Admin routes
 				leaves.DELETE("/:id", middleware.RoleMiddleware(models.RoleAdmin), r.leaveHandler.DeleteLeave)
 			}
 
+			// Invitation routes (Admin only)
+			invitations := protected.Group("/invitations")
+			invitations.Use(middleware.RoleMiddleware(models.RoleAdmin))
+			{
+				invitations.GET("", r.invitationHandler.GetInvitations)
+				invitations.POST("", r.invitationHandler.CreateInvitation)
+				invitations.DELETE("/:id", r.invitationHandler.RevokeInvitation)
+			}
+
+			// Leave type catalogue routes
+			leaveTypes := protected.Group("/leave-types")
+			{
+				leaveTypes.GET("", r.leaveTypeHandler.GetLeaveTypes)
+				leaveTypes.GET("/:code", r.leaveTypeHandler.GetLeaveType)
+				leaveTypes.POST("", middleware.RoleMiddleware(models.RoleAdmin), r.leaveTypeHandler.CreateLeaveType)
+				leaveTypes.PUT("/:code", middleware.RoleMiddleware(models.RoleAdmin), r.leaveTypeHandler.UpdateLeaveType)
+				leaveTypes.DELETE("/:code", middleware.RoleMiddleware(models.RoleAdmin), r.leaveTypeHandler.DeleteLeaveType)
+			}
+
+			// Leave quota routes (Admin only)
+			quotas := protected.Group("/leave-quotas")
+			quotas.Use(middleware.RoleMiddleware(models.RoleAdmin))
+			{
+				quotas.GET("", r.balanceHandler.GetQuotas)
+				quotas.POST("", r.balanceHandler.CreateQuota)
+				quotas.DELETE("/:id", r.balanceHandler.DeleteQuota)
+			}
+
+			// Approval workflow routes (Admin only)
+			workflows := protected.Group("/approval-workflows")
+			workflows.Use(middleware.RoleMiddleware(models.RoleAdmin))
+			{
+				workflows.GET("", r.workflowHandler.GetWorkflows)
+				workflows.PUT("/:leave_type", r.workflowHandler.SaveWorkflow)
+				workflows.DELETE("/:leave_type", r.workflowHandler.DeleteWorkflow)
+			}
+
+			// Leave policy routes (Admin only)
+			policies := protected.Group("/leave-policies")
+			policies.Use(middleware.RoleMiddleware(models.RoleAdmin))
+			{
+				policies.GET("", r.policyHandler.GetPolicies)
+				policies.PUT("/:leave_type", r.policyHandler.SavePolicy)
+				policies.DELETE("/:leave_type", r.policyHandler.DeletePolicy)
+			}
+
 			// Attendance routes
 			attendance := protected.Group("/attendance")
 			{
@@ -110,6 +260,36 @@ func (r *Router) Setup() *gin.Engine {
 					r.attendanceHandler.GetLowAttendanceStudents)
 			}
 
+			// Academic calendar routes
+			calendar := protected.Group("/calendar")
+			{
+				calendar.GET("", r.calendarHandler.GetCalendar)
+				calendar.POST("/terms", middleware.RoleMiddleware(models.RoleAdmin), r.calendarHandler.CreateTerm)
+				calendar.DELETE("/terms/:id", middleware.RoleMiddleware(models.RoleAdmin), r.calendarHandler.DeleteTerm)
+				calendar.POST("/events", middleware.RoleMiddleware(models.RoleAdmin), r.calendarHandler.CreateEvent)
+				calendar.DELETE("/events/:id", middleware.RoleMiddleware(models.RoleAdmin), r.calendarHandler.DeleteEvent)
+				calendar.PUT("/weekly-off", middleware.RoleMiddleware(models.RoleAdmin), r.calendarHandler.SetWeeklyOff)
+			}
+
+			// Hostel gate routes
+			gate := protected.Group("/gate")
+			{
+				gate.POST("/checkout", middleware.RoleMiddleware(models.RoleSecurity), r.gatePassHandler.CheckOut)
+				gate.POST("/checkin", middleware.RoleMiddleware(models.RoleSecurity), r.gatePassHandler.CheckIn)
+				gate.GET("/out",
+					middleware.RoleMiddleware(models.RoleWarden, models.RoleAdmin),
+					r.gatePassHandler.GetCurrentlyOut)
+			}
+
+			// Delegation routes
+			delegations := protected.Group("/delegations")
+			delegations.Use(middleware.RoleMiddleware(models.RoleFaculty, models.RoleWarden, models.RoleAdmin))
+			{
+				delegations.POST("", r.delegationHandler.CreateDelegation)
+				delegations.GET("", r.delegationHandler.GetDelegations)
+				delegations.DELETE("/:id", r.delegationHandler.RevokeDelegation)
+			}
+
 			// Analytics routes (Admin only)
 			analytics := protected.Group("/analytics")
 			analytics.Use(middleware.RoleMiddleware(models.RoleAdmin))
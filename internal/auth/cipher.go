@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+var ErrUndecryptable = errors.New("value cannot be decrypted")
+
+// Cipher encrypts secrets the server must read back later, such as TOTP seeds
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// derives a separate key per purpose, like NewSigner
+func NewCipher(secret, purpose string) *Cipher {
+	key := sha256.Sum256([]byte(purpose + ":" + secret))
+	// a 32-byte key always yields a valid AES-256 block and GCM mode
+	block, _ := aes.NewCipher(key[:])
+	aead, _ := cipher.NewGCM(block)
+	return &Cipher{aead: aead}
+}
+
+func (c *Cipher) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (c *Cipher) Open(sealed string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil || len(raw) < c.aead.NonceSize() {
+		return "", ErrUndecryptable
+	}
+
+	nonce, ciphertext := raw[:c.aead.NonceSize()], raw[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrUndecryptable
+	}
+	return string(plaintext), nil
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -12,6 +14,8 @@ type Claims struct {
 	UserID uint        `json:"user_id"`
 	Email  string      `json:"email"`
 	Role   models.Role `json:"role"`
+	// whether the session was started with a second factor
+	MFA bool `json:"mfa,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -27,12 +31,25 @@ func NewJWTService(secretKey string, expiry time.Duration) *JWTService {
 	}
 }
 
-func (s *JWTService) GenerateToken(user *models.User) (string, error) {
+// the lifetime of the tokens it issues
+func (s *JWTService) Expiry() time.Duration {
+	return s.expiry
+}
+
+// issues an access token with a random jti so it can be revoked on its own
+func (s *JWTService) GenerateToken(user *models.User, mfa bool) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+
 	claims := &Claims{
 		UserID: user.ID,
 		Email:  user.Email,
 		Role:   user.Role,
+		MFA:    mfa,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(jti),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(s.expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
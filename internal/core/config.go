@@ -2,21 +2,36 @@ package core
 
 import (
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	SMTP     SMTPConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	JWT        JWTConfig
+	SMTP       SMTPConfig
+	Storage    StorageConfig
+	Attachment AttachmentConfig
+	GatePass   GatePassConfig
+	SLA        SLAConfig
+	Policy     PolicyConfig
+	Timetable  TimetableConfig
+	Consent    ConsentConfig
+	Invitation InvitationConfig
+	Account    AccountConfig
+	MFA        MFAConfig
+	Login      LoginConfig
 }
 
 type ServerConfig struct {
 	Host string
 	Port string
+	// public address used in links sent out by email
+	BaseURL string
 }
 
 type DatabaseConfig struct {
@@ -30,7 +45,9 @@ type DatabaseConfig struct {
 
 type JWTConfig struct {
 	Secret string
-	Expiry time.Duration
+	// lifetime of access tokens; keep it short since refresh tokens renew them
+	Expiry        time.Duration
+	RefreshExpiry time.Duration
 }
 
 type SMTPConfig struct {
@@ -40,6 +57,94 @@ type SMTPConfig struct {
 	Password string
 }
 
+type StorageConfig struct {
+	Driver    string
+	LocalPath string
+}
+
+type AttachmentConfig struct {
+	MaxSize      int64
+	AllowedTypes []string
+	// leave types that need a document once they run longer than the given days
+	RequiredOverDays map[string]int
+}
+
+type GatePassConfig struct {
+	Secret string
+}
+
+type ConsentConfig struct {
+	// leave types that need a guardian's consent when the student is a minor or lives in a hostel
+	LeaveTypes []string
+	Secret     string
+	LinkTTL    time.Duration
+}
+
+type InvitationConfig struct {
+	// whether students may sign up without an invitation; other roles always need one
+	StudentSelfRegistration bool
+	Secret                  string
+	TTL                     time.Duration
+	// first admin account, created at startup while no admin exists
+	BootstrapAdminEmail    string
+	BootstrapAdminPassword string
+}
+
+type AccountConfig struct {
+	// whether accounts must confirm their email address before they can sign in
+	RequireEmailVerification bool
+	VerificationTTL          time.Duration
+	PasswordResetTTL         time.Duration
+}
+
+type MFAConfig struct {
+	// roles that cannot use the API until they have two-factor authentication on
+	RequiredRoles []string
+	// name shown next to the account in authenticator apps
+	Issuer       string
+	Secret       string
+	ChallengeTTL time.Duration
+}
+
+type LoginConfig struct {
+	// failures allowed per account, and per client address, before sign-in locks
+	MaxAttempts   int
+	IPMaxAttempts int
+	// failures older than this are forgotten
+	Window time.Duration
+	// the first lockout; each further failure doubles it up to MaxLockout
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+}
+
+type TimetableConfig struct {
+	// teaching periods in a day; the first half is the forenoon session
+	PeriodsPerDay int
+}
+
+type PolicyConfig struct {
+	// optional YAML file whose leave policies are stored at startup
+	File string
+	// how many days after an absence Medical and Emergency leave may still be filed
+	RetroactiveWindowDays int
+}
+
+type SLAConfig struct {
+	// how long each leave type may wait on one approver before escalating
+	Durations            map[string]time.Duration
+	Default              time.Duration
+	AutoApproveEmergency bool
+	CheckInterval        time.Duration
+}
+
+// returns the approval deadline for the leave type
+func (c SLAConfig) For(leaveType string) time.Duration {
+	if d, ok := c.Durations[leaveType]; ok {
+		return d
+	}
+	return c.Default
+}
+
 func LoadConfig() (*Config, error) {
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
@@ -53,17 +158,50 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("DB_HOST", "localhost")
 	viper.SetDefault("DB_PORT", "5432")
 	viper.SetDefault("DB_SSLMODE", "disable")
-	viper.SetDefault("JWT_EXPIRY", "24h")
+	viper.SetDefault("JWT_EXPIRY", "15m")
+	viper.SetDefault("JWT_REFRESH_EXPIRY", "720h")
+	viper.SetDefault("STORAGE_DRIVER", "local")
+	viper.SetDefault("STORAGE_LOCAL_PATH", "uploads")
+	viper.SetDefault("ATTACHMENT_MAX_SIZE", 5<<20)
+	viper.SetDefault("ATTACHMENT_ALLOWED_TYPES", "application/pdf,image/jpeg,image/png")
+	viper.SetDefault("ATTACHMENT_REQUIRED_OVER_DAYS", "Medical:2")
+	viper.SetDefault("GATE_PASS_SECRET", viper.GetString("JWT_SECRET"))
+	viper.SetDefault("SLA_DURATIONS", "Emergency:4h,Medical:24h,Personal:48h,Academic:48h")
+	viper.SetDefault("SLA_DEFAULT", "48h")
+	viper.SetDefault("SLA_AUTO_APPROVE_EMERGENCY", false)
+	viper.SetDefault("SLA_CHECK_INTERVAL", "15m")
+	viper.SetDefault("RETROACTIVE_WINDOW_DAYS", 7)
+	viper.SetDefault("PERIODS_PER_DAY", 8)
+	viper.SetDefault("APP_BASE_URL", "http://localhost:8080")
+	viper.SetDefault("CONSENT_LEAVE_TYPES", "")
+	viper.SetDefault("CONSENT_SECRET", viper.GetString("JWT_SECRET"))
+	viper.SetDefault("CONSENT_LINK_TTL", "72h")
+	viper.SetDefault("STUDENT_SELF_REGISTRATION", true)
+	viper.SetDefault("INVITATION_SECRET", viper.GetString("JWT_SECRET"))
+	viper.SetDefault("INVITATION_TTL", "168h")
+	viper.SetDefault("REQUIRE_EMAIL_VERIFICATION", true)
+	viper.SetDefault("EMAIL_VERIFICATION_TTL", "48h")
+	viper.SetDefault("PASSWORD_RESET_TTL", "1h")
+	viper.SetDefault("MFA_REQUIRED_ROLES", "")
+	viper.SetDefault("MFA_ISSUER", "Campus Leave System")
+	viper.SetDefault("MFA_SECRET", viper.GetString("JWT_SECRET"))
+	viper.SetDefault("MFA_CHALLENGE_TTL", "5m")
+	viper.SetDefault("LOGIN_MAX_ATTEMPTS", 5)
+	viper.SetDefault("LOGIN_IP_MAX_ATTEMPTS", 20)
+	viper.SetDefault("LOGIN_ATTEMPT_WINDOW", "15m")
+	viper.SetDefault("LOGIN_BASE_LOCKOUT", "1m")
+	viper.SetDefault("LOGIN_MAX_LOCKOUT", "1h")
 
 	expiry, err := time.ParseDuration(viper.GetString("JWT_EXPIRY"))
 	if err != nil {
-		expiry = 24 * time.Hour
+		expiry = 15 * time.Minute
 	}
 
 	return &Config{
 		Server: ServerConfig{
-			Host: viper.GetString("SERVER_HOST"),
-			Port: viper.GetString("SERVER_PORT"),
+			Host:    viper.GetString("SERVER_HOST"),
+			Port:    viper.GetString("SERVER_PORT"),
+			BaseURL: strings.TrimRight(viper.GetString("APP_BASE_URL"), "/"),
 		},
 		Database: DatabaseConfig{
 			Host:     viper.GetString("DB_HOST"),
@@ -74,8 +212,9 @@ func LoadConfig() (*Config, error) {
 			SSLMode:  viper.GetString("DB_SSLMODE"),
 		},
 		JWT: JWTConfig{
-			Secret: viper.GetString("JWT_SECRET"),
-			Expiry: expiry,
+			Secret:        viper.GetString("JWT_SECRET"),
+			Expiry:        expiry,
+			RefreshExpiry: viper.GetDuration("JWT_REFRESH_EXPIRY"),
 		},
 		SMTP: SMTPConfig{
 			Host:     viper.GetString("SMTP_HOST"),
@@ -83,5 +222,105 @@ func LoadConfig() (*Config, error) {
 			User:     viper.GetString("SMTP_USER"),
 			Password: viper.GetString("SMTP_PASSWORD"),
 		},
+		Storage: StorageConfig{
+			Driver:    viper.GetString("STORAGE_DRIVER"),
+			LocalPath: viper.GetString("STORAGE_LOCAL_PATH"),
+		},
+		Attachment: AttachmentConfig{
+			MaxSize:          viper.GetInt64("ATTACHMENT_MAX_SIZE"),
+			AllowedTypes:     splitList(viper.GetString("ATTACHMENT_ALLOWED_TYPES")),
+			RequiredOverDays: parseDayLimits(viper.GetString("ATTACHMENT_REQUIRED_OVER_DAYS")),
+		},
+		GatePass: GatePassConfig{
+			Secret: viper.GetString("GATE_PASS_SECRET"),
+		},
+		SLA: SLAConfig{
+			Durations:            parseDurations(viper.GetString("SLA_DURATIONS")),
+			Default:              viper.GetDuration("SLA_DEFAULT"),
+			AutoApproveEmergency: viper.GetBool("SLA_AUTO_APPROVE_EMERGENCY"),
+			CheckInterval:        viper.GetDuration("SLA_CHECK_INTERVAL"),
+		},
+		Policy: PolicyConfig{
+			File:                  viper.GetString("POLICY_FILE"),
+			RetroactiveWindowDays: viper.GetInt("RETROACTIVE_WINDOW_DAYS"),
+		},
+		Timetable: TimetableConfig{
+			PeriodsPerDay: viper.GetInt("PERIODS_PER_DAY"),
+		},
+		Consent: ConsentConfig{
+			LeaveTypes: splitList(viper.GetString("CONSENT_LEAVE_TYPES")),
+			Secret:     viper.GetString("CONSENT_SECRET"),
+			LinkTTL:    viper.GetDuration("CONSENT_LINK_TTL"),
+		},
+		Invitation: InvitationConfig{
+			StudentSelfRegistration: viper.GetBool("STUDENT_SELF_REGISTRATION"),
+			Secret:                  viper.GetString("INVITATION_SECRET"),
+			TTL:                     viper.GetDuration("INVITATION_TTL"),
+			BootstrapAdminEmail:     viper.GetString("BOOTSTRAP_ADMIN_EMAIL"),
+			BootstrapAdminPassword:  viper.GetString("BOOTSTRAP_ADMIN_PASSWORD"),
+		},
+		Account: AccountConfig{
+			RequireEmailVerification: viper.GetBool("REQUIRE_EMAIL_VERIFICATION"),
+			VerificationTTL:          viper.GetDuration("EMAIL_VERIFICATION_TTL"),
+			PasswordResetTTL:         viper.GetDuration("PASSWORD_RESET_TTL"),
+		},
+		MFA: MFAConfig{
+			RequiredRoles: splitList(viper.GetString("MFA_REQUIRED_ROLES")),
+			Issuer:        viper.GetString("MFA_ISSUER"),
+			Secret:        viper.GetString("MFA_SECRET"),
+			ChallengeTTL:  viper.GetDuration("MFA_CHALLENGE_TTL"),
+		},
+		Login: LoginConfig{
+			MaxAttempts:   viper.GetInt("LOGIN_MAX_ATTEMPTS"),
+			IPMaxAttempts: viper.GetInt("LOGIN_IP_MAX_ATTEMPTS"),
+			Window:        viper.GetDuration("LOGIN_ATTEMPT_WINDOW"),
+			BaseLockout:   viper.GetDuration("LOGIN_BASE_LOCKOUT"),
+			MaxLockout:    viper.GetDuration("LOGIN_MAX_LOCKOUT"),
+		},
 	}, nil
 }
+
+// splits a comma separated setting, dropping blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parses "Medical:2,Academic:5" into a map, skipping malformed entries
+func parseDayLimits(value string) map[string]int {
+	limits := make(map[string]int)
+	for _, item := range splitList(value) {
+		name, days, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(days))
+		if err != nil {
+			continue
+		}
+		limits[strings.TrimSpace(name)] = n
+	}
+	return limits
+}
+
+// parses "Emergency:4h,Personal:48h" into a map, skipping malformed entries
+func parseDurations(value string) map[string]time.Duration {
+	durations := make(map[string]time.Duration)
+	for _, item := range splitList(value) {
+		name, raw, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(raw))
+		if err != nil {
+			continue
+		}
+		durations[strings.TrimSpace(name)] = d
+	}
+	return durations
+}
@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// ApprovalWorkflow is the ordered chain of approvers a leave type must pass through
+type ApprovalWorkflow struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	LeaveType LeaveType      `gorm:"type:varchar(50);uniqueIndex;not null" json:"leave_type"`
+	Steps     []ApprovalStep `gorm:"foreignKey:WorkflowID;constraint:OnDelete:CASCADE" json:"steps"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// ApprovalStep is skipped for day scholars when HostelOnly is set
+type ApprovalStep struct {
+	ID           uint `gorm:"primaryKey" json:"id"`
+	WorkflowID   uint `gorm:"index;not null" json:"workflow_id"`
+	StepOrder    int  `gorm:"not null" json:"step_order"`
+	ApproverRole Role `gorm:"type:varchar(20);not null" json:"approver_role"`
+	HostelOnly   bool `gorm:"default:false" json:"hostel_only"`
+}
+
+// LeaveApproval records a single approver's decision on one step
+type LeaveApproval struct {
+	ID           uint        `gorm:"primaryKey" json:"id"`
+	LeaveID      uint        `gorm:"index;not null" json:"leave_id"`
+	Step         int         `gorm:"not null" json:"step"`
+	ApproverID   uint        `gorm:"index;not null" json:"approver_id"`
+	Approver     User        `gorm:"foreignKey:ApproverID" json:"approver,omitempty"`
+	OnBehalfOfID *uint       `gorm:"index" json:"on_behalf_of_id,omitempty"`
+	ApproverRole Role        `gorm:"type:varchar(20);not null" json:"approver_role"`
+	Decision     LeaveStatus `gorm:"type:varchar(20);not null" json:"decision"`
+	Remarks      *string     `gorm:"type:text" json:"remarks,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+// retroactive requests are regularised by the student's faculty alone
+var RetroactiveApprovalSteps = []ApprovalStep{
+	{StepOrder: 1, ApproverRole: RoleFaculty},
+}
+
+// used when no workflow has been configured for a leave type
+var DefaultApprovalSteps = []ApprovalStep{
+	{StepOrder: 1, ApproverRole: RoleFaculty},
+	{StepOrder: 2, ApproverRole: RoleWarden, HostelOnly: true},
+}
+
+// checks whether the step applies to the student
+func (s ApprovalStep) AppliesTo(student *User) bool {
+	return !s.HostelOnly || student.Hostel != ""
+}
+
+type BulkDecisionOutcome string
+
+const (
+	BulkOutcomeApproved       BulkDecisionOutcome = "approved"
+	BulkOutcomeRejected       BulkDecisionOutcome = "rejected"
+	BulkOutcomeAlreadyDecided BulkDecisionOutcome = "already_decided"
+	BulkOutcomeOutOfScope     BulkDecisionOutcome = "out_of_scope"
+	BulkOutcomeNotFound       BulkDecisionOutcome = "not_found"
+	BulkOutcomeFailed         BulkDecisionOutcome = "failed"
+)
+
+// BulkDecisionResult reports what happened to one request in a bulk decision
+type BulkDecisionResult struct {
+	LeaveID uint                `json:"leave_id"`
+	Outcome BulkDecisionOutcome `json:"outcome"`
+	Error   string              `json:"error,omitempty"`
+}
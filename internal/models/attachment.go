@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// LeaveAttachment is a supporting document such as a medical certificate
+type LeaveAttachment struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	LeaveID     uint      `gorm:"index;not null" json:"leave_id"`
+	FileName    string    `gorm:"type:varchar(255);not null" json:"file_name"`
+	ContentType string    `gorm:"type:varchar(100);not null" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	StorageKey  string    `gorm:"type:varchar(500);not null" json:"-"`
+	UploadedBy  uint      `gorm:"not null" json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
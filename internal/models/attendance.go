@@ -8,16 +8,21 @@ type Attendance struct {
 	Student   User      `gorm:"foreignKey:StudentID" json:"student,omitempty"`
 	Date      time.Time `gorm:"index;not null" json:"date" binding:"required"`
 	Present   bool      `gorm:"default:false" json:"present"`
-	MarkedBy  uint      `gorm:"not null" json:"marked_by"`
-	Marker    User      `gorm:"foreignKey:MarkedBy" json:"marker,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	// nil when the system marked it, as for leave approved automatically
+	MarkedBy    *uint     `gorm:"index" json:"marked_by,omitempty"`
+	Marker      *User     `gorm:"foreignKey:MarkedBy" json:"marker,omitempty"`
+	Period      int       `gorm:"default:0" json:"period,omitempty"`
+	LeaveID     *uint     `gorm:"index" json:"leave_id,omitempty"`
+	Regularised bool      `gorm:"default:false" json:"regularised"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-//represents attendance statistics
+// represents attendance statistics
 type AttendanceStats struct {
 	StudentID            uint    `json:"student_id"`
-	PresentDays          int64   `json:"present_days"`
-	TotalDays            int64   `json:"total_days"`
+	PresentDays          float64 `json:"present_days"`
+	ExcusedDays          float64 `json:"excused_days"`
+	TotalDays            float64 `json:"total_days"`
 	AttendancePercentage float64 `json:"attendance_percentage"`
 }
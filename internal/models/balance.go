@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+type QuotaPeriod string
+type LedgerEntryType string
+
+const (
+	QuotaPeriodAnnual   QuotaPeriod = "annual"
+	QuotaPeriodSemester QuotaPeriod = "semester"
+
+	LedgerEntryDebit  LedgerEntryType = "debit"
+	LedgerEntryCredit LedgerEntryType = "credit"
+)
+
+// LeaveQuota caps the number of days a student may take of one leave type per period.
+// Empty Dept and zero Year match every student.
+type LeaveQuota struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	LeaveType LeaveType   `gorm:"type:varchar(50);not null;index" json:"leave_type"`
+	Period    QuotaPeriod `gorm:"type:varchar(20);not null;default:'annual'" json:"period"`
+	Days      float64     `gorm:"not null" json:"days"`
+	Dept      string      `gorm:"type:varchar(100)" json:"dept,omitempty"`
+	Year      int         `gorm:"default:0" json:"year,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// LeaveLedgerEntry is one debit or credit against a student's balance
+type LeaveLedgerEntry struct {
+	ID        uint            `gorm:"primaryKey" json:"id"`
+	StudentID uint            `gorm:"index;not null" json:"student_id"`
+	LeaveID   uint            `gorm:"index;not null" json:"leave_id"`
+	LeaveType LeaveType       `gorm:"type:varchar(50);not null" json:"leave_type"`
+	EntryType LedgerEntryType `gorm:"type:varchar(10);not null" json:"entry_type"`
+	Days      float64         `gorm:"not null" json:"days"`
+	Date      time.Time       `gorm:"index;not null" json:"date"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type LeaveBalance struct {
+	LeaveType   LeaveType   `json:"leave_type"`
+	Period      QuotaPeriod `json:"period,omitempty"`
+	PeriodStart *time.Time  `json:"period_start,omitempty"`
+	PeriodEnd   *time.Time  `json:"period_end,omitempty"`
+	Quota       float64     `json:"quota"`
+	Used        float64     `json:"used"`
+	Remaining   float64     `json:"remaining"`
+	Unlimited   bool        `json:"unlimited"`
+}
+
+// returns the first and last day of the period containing date
+func (p QuotaPeriod) Bounds(date time.Time) (time.Time, time.Time) {
+	year := date.Year()
+	if p == QuotaPeriodSemester {
+		if date.Month() <= time.June {
+			return time.Date(year, time.January, 1, 0, 0, 0, 0, date.Location()),
+				time.Date(year, time.June, 30, 0, 0, 0, 0, date.Location())
+		}
+		return time.Date(year, time.July, 1, 0, 0, 0, 0, date.Location()),
+			time.Date(year, time.December, 31, 0, 0, 0, 0, date.Location())
+	}
+	return time.Date(year, time.January, 1, 0, 0, 0, 0, date.Location()),
+		time.Date(year, time.December, 31, 0, 0, 0, 0, date.Location())
+}
+
+// checks whether the quota applies to the student
+func (q *LeaveQuota) Matches(student *User) bool {
+	if q.Dept != "" && q.Dept != student.Dept {
+		return false
+	}
+	if q.Year != 0 && q.Year != student.Year {
+		return false
+	}
+	return true
+}
+
+// higher values win when several quotas match the same student
+func (q *LeaveQuota) Specificity() int {
+	score := 0
+	if q.Dept != "" {
+		score += 2
+	}
+	if q.Year != 0 {
+		score++
+	}
+	return score
+}
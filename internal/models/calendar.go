@@ -0,0 +1,123 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+type CalendarEventKind string
+
+const (
+	CalendarEventHoliday  CalendarEventKind = "holiday"
+	CalendarEventExamWeek CalendarEventKind = "exam_week"
+)
+
+type AcademicTerm struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	StartDate time.Time `gorm:"index;not null" json:"start_date"`
+	EndDate   time.Time `gorm:"index;not null" json:"end_date"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CalendarEvent marks holidays (non-working) and exam weeks (working, but restricted)
+type CalendarEvent struct {
+	ID        uint              `gorm:"primaryKey" json:"id"`
+	Name      string            `gorm:"type:varchar(100);not null" json:"name"`
+	Kind      CalendarEventKind `gorm:"type:varchar(20);not null;index" json:"kind"`
+	StartDate time.Time         `gorm:"index;not null" json:"start_date"`
+	EndDate   time.Time         `gorm:"index;not null" json:"end_date"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+type WeeklyOff struct {
+	ID      uint         `gorm:"primaryKey" json:"id"`
+	Weekday time.Weekday `gorm:"uniqueIndex;not null" json:"weekday"`
+}
+
+// used until an admin configures the weekly offs
+var DefaultWeeklyOff = []time.Weekday{time.Sunday}
+
+// Calendar is a snapshot of the academic calendar used for day counting
+type Calendar struct {
+	Terms     []AcademicTerm  `json:"terms"`
+	Events    []CalendarEvent `json:"events"`
+	WeeklyOff []time.Weekday  `json:"weekly_off"`
+}
+
+// a day is working unless it is a weekly off, a holiday, or outside every configured term
+func (c *Calendar) IsWorkingDay(date time.Time) bool {
+	day := DateOnly(date)
+
+	if slices.Contains(c.WeeklyOff, day.Weekday()) {
+		return false
+	}
+
+	for _, event := range c.Events {
+		if event.Kind == CalendarEventHoliday && covers(event.StartDate, event.EndDate, day) {
+			return false
+		}
+	}
+
+	if len(c.Terms) == 0 {
+		return true
+	}
+	for _, term := range c.Terms {
+		if covers(term.StartDate, term.EndDate, day) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Calendar) InExamWeek(date time.Time) bool {
+	day := DateOnly(date)
+	for _, event := range c.Events {
+		if event.Kind == CalendarEventExamWeek && covers(event.StartDate, event.EndDate, day) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Calendar) WorkingDates(start, end time.Time) []time.Time {
+	var dates []time.Time
+	for day := DateOnly(start); !day.After(DateOnly(end)); day = day.AddDate(0, 0, 1) {
+		if c.IsWorkingDay(day) {
+			dates = append(dates, day)
+		}
+	}
+	return dates
+}
+
+func (c *Calendar) NonWorkingDates(start, end time.Time) []time.Time {
+	var dates []time.Time
+	for day := DateOnly(start); !day.After(DateOnly(end)); day = day.AddDate(0, 0, 1) {
+		if !c.IsWorkingDay(day) {
+			dates = append(dates, day)
+		}
+	}
+	return dates
+}
+
+// strips the time of day so dates compare cleanly
+func DateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// accepts full English weekday names, case-insensitively
+func ParseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+func covers(start, end, day time.Time) bool {
+	return !day.Before(DateOnly(start)) && !day.After(DateOnly(end))
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// LeaveComment is one message in the conversation between a student and the approvers of their request
+type LeaveComment struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	LeaveID       uint      `gorm:"index;not null" json:"leave_id"`
+	AuthorID      uint      `gorm:"index;not null" json:"author_id"`
+	Author        User      `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+	Body          string    `gorm:"type:text;not null" json:"body"`
+	RequestedInfo bool      `gorm:"default:false" json:"requested_info"`
+	CreatedAt     time.Time `json:"created_at"`
+}
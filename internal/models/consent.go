@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type ConsentStatus string
+
+const (
+	ConsentPending  ConsentStatus = "pending"
+	ConsentGranted  ConsentStatus = "granted"
+	ConsentDeclined ConsentStatus = "declined"
+)
+
+// GuardianConsent is a guardian's answer to a student's leave, collected through a one-time link
+type GuardianConsent struct {
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	LeaveID       uint          `gorm:"uniqueIndex;not null" json:"leave_id"`
+	Leave         *LeaveRequest `gorm:"foreignKey:LeaveID" json:"leave,omitempty"`
+	GuardianName  string        `gorm:"type:varchar(100)" json:"guardian_name"`
+	GuardianEmail string        `gorm:"type:varchar(255);not null" json:"guardian_email"`
+	// changes with every link sent and is cleared once used, so each link works once
+	Nonce       string        `gorm:"type:varchar(64)" json:"-"`
+	Status      ConsentStatus `gorm:"type:varchar(20);default:'pending'" json:"status"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+	RespondedAt *time.Time    `json:"responded_at,omitempty"`
+	Remarks     *string       `gorm:"type:text" json:"remarks,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
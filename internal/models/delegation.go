@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type DelegationScope string
+
+const (
+	DelegationScopeAll           DelegationScope = "all"
+	DelegationScopeLeaveApproval DelegationScope = "leave_approval"
+)
+
+// Delegation lets a colleague act for an absent faculty member, warden or admin
+type Delegation struct {
+	ID          uint            `gorm:"primaryKey" json:"id"`
+	DelegatorID uint            `gorm:"index;not null" json:"delegator_id"`
+	Delegator   *User           `gorm:"foreignKey:DelegatorID" json:"delegator,omitempty"`
+	DelegateID  uint            `gorm:"index;not null" json:"delegate_id"`
+	Delegate    *User           `gorm:"foreignKey:DelegateID" json:"delegate,omitempty"`
+	StartDate   time.Time       `gorm:"not null" json:"start_date"`
+	EndDate     time.Time       `gorm:"not null" json:"end_date"`
+	Scope       DelegationScope `gorm:"type:varchar(20);not null;default:'all'" json:"scope"`
+	Reason      string          `gorm:"type:text" json:"reason,omitempty"`
+	RevokedAt   *time.Time      `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// a full delegation covers every duty
+func (d *Delegation) Covers(scope DelegationScope) bool {
+	return d.Scope == DelegationScopeAll || d.Scope == scope
+}
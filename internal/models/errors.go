@@ -1,15 +1,142 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
-	ErrInvalidDateRange   = errors.New("end date cannot be before start date")
-	ErrPastDate           = errors.New("start date cannot be in the past")
-	ErrOverlappingLeave   = errors.New("leave request overlaps with existing leave")
-	ErrUnauthorized       = errors.New("unauthorized access")
-	ErrInvalidCredentials = errors.New("invalid email or password")
-	ErrUserNotFound       = errors.New("user not found")
-	ErrLeaveNotFound      = errors.New("leave request not found")
-	ErrInvalidRole        = errors.New("invalid role for this operation")
-	ErrAttendanceExists   = errors.New("attendance already marked for this date")
+	ErrInvalidDateRange      = errors.New("end date cannot be before start date")
+	ErrPastDate              = errors.New("start date cannot be in the past")
+	ErrOverlappingLeave      = errors.New("leave request overlaps with existing leave")
+	ErrUnauthorized          = errors.New("unauthorized access")
+	ErrInvalidCredentials    = errors.New("invalid email or password")
+	ErrUserNotFound          = errors.New("user not found")
+	ErrLeaveNotFound         = errors.New("leave request not found")
+	ErrInvalidRole           = errors.New("invalid role for this operation")
+	ErrAttendanceExists      = errors.New("attendance already marked for this date")
+	ErrInsufficientBalance   = errors.New("insufficient leave balance")
+	ErrQuotaNotFound         = errors.New("leave quota not found")
+	ErrLeaveNotPending       = errors.New("leave request has already been decided")
+	ErrNotAwaitingApprover   = errors.New("leave request is not awaiting your approval")
+	ErrWorkflowNotFound      = errors.New("approval workflow not found")
+	ErrOutOfScope            = errors.New("student is outside your approval scope")
+	ErrLeaveNotEditable      = errors.New("only pending leave requests can be edited")
+	ErrLeaveNotCancellable   = errors.New("only approved leave can be cancelled")
+	ErrLeaveAlreadyStarted   = errors.New("leave has already started")
+	ErrLeaveNotExtendable    = errors.New("only approved leave can be extended")
+	ErrInvalidExtension      = errors.New("extension must end after the current end date")
+	ErrExtensionPending      = errors.New("an extension for this leave is already awaiting approval")
+	ErrExtensionNotEditable  = errors.New("extensions cannot be edited or cancelled on their own")
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrAttachmentTooLarge    = errors.New("attachment exceeds the maximum allowed size")
+	ErrUnsupportedFileType   = errors.New("attachment file type is not allowed")
+	ErrAttachmentRequired    = errors.New("a supporting document must be attached before this leave can be approved")
+	ErrNoWorkingDays         = errors.New("leave does not cover any working days")
+	ErrCalendarEntryNotFound = errors.New("calendar entry not found")
+	ErrGatePassNotFound      = errors.New("gate pass not found")
+	ErrInvalidGatePass       = errors.New("gate pass is invalid")
+	ErrGatePassNotUsable     = errors.New("gate pass cannot be used in its current state")
+	ErrGatePassNotValidNow   = errors.New("gate pass is not valid at this time")
+	ErrDelegationNotFound    = errors.New("delegation not found")
+	ErrInvalidDelegation     = errors.New("approval duties can only be delegated between different staff members")
+	ErrPolicyViolation       = errors.New("leave request violates the leave policy")
+	ErrPolicyNotFound        = errors.New("leave policy not found")
+	ErrRetroactiveNotAllowed = errors.New("only Medical and Emergency leave can be filed retroactively")
+	ErrInvalidSession        = errors.New("session must be full_day, forenoon, afternoon or periods")
+	ErrInvalidPeriodRange    = errors.New("period range is outside the timetable")
+	ErrPartialDayRange       = errors.New("half-day and period leave must start and end on the same date")
+	ErrLeaveTypeNotFound     = errors.New("leave type not found")
+	ErrLeaveTypeInactive     = errors.New("leave type is no longer available")
+	ErrLeaveTypeExists       = errors.New("a leave type with this code already exists")
+	ErrLeaveTypeInUse        = errors.New("leave type has requests filed against it; deactivate it instead")
+	ErrBulkSelectionRequired = errors.New("either leave ids or a filter must be given")
+	ErrInvalidTransition     = errors.New("leave request cannot move to that status")
+	ErrInvalidRecurrence     = errors.New("recurrence must repeat weekly or monthly, without overlapping itself, until a date on or after the start")
+	ErrTooManyOccurrences    = errors.New("recurring leave series is too long")
+	ErrNotRecurring          = errors.New("leave request is not a recurring series")
+	ErrOccurrenceNotFound    = errors.New("leave occurrence not found")
+	ErrGuardianRequired      = errors.New("a guardian email must be on file before requesting this leave")
+	ErrConsentPending        = errors.New("the guardian has not consented to this leave yet")
+	ErrConsentDeclined       = errors.New("the guardian declined consent for this leave")
+	ErrConsentNotFound       = errors.New("guardian consent not found")
+	ErrInvalidConsentLink    = errors.New("consent link is invalid, used or expired")
+	ErrStepsRemaining        = errors.New("later approval steps remain, so the request cannot be approved automatically")
+	ErrGuardianLocked        = errors.New("guardian details cannot change while a guardian consent is pending")
+	ErrInvitationRequired    = errors.New("an invitation is required to register this account")
+	ErrInvalidInvitation     = errors.New("invitation is invalid, used, revoked or expired")
+	ErrInvitationNotFound    = errors.New("invitation not found")
+	ErrInvitationPlacement   = errors.New("department and hostel for this account are set by its invitation")
+	ErrEmailTaken            = errors.New("an account with this email already exists")
+	ErrInvalidRefreshToken   = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused    = errors.New("refresh token was already used; every session it belongs to has been signed out")
+	ErrTokenRevoked          = errors.New("token has been revoked")
+	ErrEmailNotVerified      = errors.New("email address has not been verified")
+	ErrInvalidAccountToken   = errors.New("link is invalid, expired or already used")
+	ErrMFARequired           = errors.New("two-factor authentication must be set up for this role")
+	ErrMFANotEnrolled        = errors.New("two-factor authentication is not set up")
+	ErrMFAAlreadyEnabled     = errors.New("two-factor authentication is already on")
+	ErrMFAEnforced           = errors.New("two-factor authentication cannot be turned off for this role")
+	ErrInvalidMFACode        = errors.New("authentication code is invalid or already used")
+	ErrTooManyAttempts       = errors.New("too many failed sign-in attempts")
 )
+
+// returned when a leave request exceeds the student's remaining quota
+type InsufficientBalanceError struct {
+	LeaveType LeaveType `json:"leave_type"`
+	Requested float64   `json:"requested"`
+	Remaining float64   `json:"remaining"`
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("insufficient %s leave balance: requested %g day(s), %g remaining",
+		e.LeaveType, e.Requested, e.Remaining)
+}
+
+func (e *InsufficientBalanceError) Is(target error) bool {
+	return target == ErrInsufficientBalance
+}
+
+// returned with every rule a leave request breaks, not just the first
+type PolicyViolationError struct {
+	Violations []PolicyViolation `json:"violations"`
+}
+
+func (e *PolicyViolationError) Error() string {
+	if len(e.Violations) == 1 {
+		return e.Violations[0].Message
+	}
+	return fmt.Sprintf("leave request violates %d policy rules", len(e.Violations))
+}
+
+func (e *PolicyViolationError) Is(target error) bool {
+	return target == ErrPolicyViolation
+}
+
+// returned when the state machine does not allow a leave request to move between two statuses
+type InvalidTransitionError struct {
+	From LeaveStatus `json:"from"`
+	To   LeaveStatus `json:"to"`
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("leave request cannot move from %s to %s", e.From, e.To)
+}
+
+func (e *InvalidTransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// returned while sign-in is locked for an account or address after repeated failures
+type LoginThrottledError struct {
+	RetryAfter time.Duration `json:"retry_after"`
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed sign-in attempts; try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginThrottledError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
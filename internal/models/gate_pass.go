@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+type GatePassStatus string
+
+const (
+	GatePassIssued   GatePassStatus = "issued"
+	GatePassOut      GatePassStatus = "out"
+	GatePassReturned GatePassStatus = "returned"
+	GatePassRevoked  GatePassStatus = "revoked"
+)
+
+// GatePass lets a hostel resident on approved leave through the gate
+type GatePass struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	LeaveID      uint           `gorm:"uniqueIndex;not null" json:"leave_id"`
+	Leave        *LeaveRequest  `gorm:"foreignKey:LeaveID" json:"leave,omitempty"`
+	StudentID    uint           `gorm:"index;not null" json:"student_id"`
+	Student      *User          `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	Hostel       string         `gorm:"type:varchar(100);index;not null" json:"hostel"`
+	Code         string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"code,omitempty"`
+	Status       GatePassStatus `gorm:"type:varchar(20);index;default:'issued'" json:"status"`
+	CheckedOutAt *time.Time     `json:"checked_out_at,omitempty"`
+	CheckedOutBy *uint          `json:"checked_out_by,omitempty"`
+	CheckedInAt  *time.Time     `json:"checked_in_at,omitempty"`
+	CheckedInBy  *uint          `json:"checked_in_by,omitempty"`
+	LateReturn   bool           `gorm:"default:false" json:"late_return"`
+	Overdue      bool           `gorm:"-" json:"overdue,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// students are due back by the end of the leave's last day
+func ReturnDeadline(leave *LeaveRequest) time.Time {
+	return DateOnly(leave.EndDate).AddDate(0, 0, 1)
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Invitation lets an admin onboard a staff account, since only students may sign themselves up
+type Invitation struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Email       string `gorm:"type:varchar(255);index;not null" json:"email"`
+	Role        Role   `gorm:"type:varchar(20);not null" json:"role"`
+	Dept        string `gorm:"type:varchar(100)" json:"dept,omitempty"`
+	Hostel      string `gorm:"type:varchar(100)" json:"hostel,omitempty"`
+	InvitedByID uint   `gorm:"index;not null" json:"invited_by_id"`
+	InvitedBy   *User  `gorm:"foreignKey:InvitedByID" json:"invited_by,omitempty"`
+	// ties the signed token to this row so it cannot be reused once accepted or revoked
+	Nonce          string     `gorm:"type:varchar(64);not null" json:"-"`
+	ExpiresAt      time.Time  `gorm:"not null" json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	AcceptedUserID *uint      `json:"accepted_user_id,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// open invitations have not been accepted, revoked or let expire
+func (i *Invitation) IsOpenAt(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// CreatedInvitation is returned to the admin once, carrying the token the invitee registers with
+type CreatedInvitation struct {
+	Invitation *Invitation `json:"invitation"`
+	Token      string      `json:"token"`
+	Link       string      `json:"link"`
+}
@@ -1,44 +1,260 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type LeaveType string
 type LeaveStatus string
+type SLAState string
+type LeaveSession string
 
 const (
+	// codes of the seeded catalogue entries that carry built-in behaviour
 	LeaveTypeMedical   LeaveType = "Medical"
 	LeaveTypePersonal  LeaveType = "Personal"
 	LeaveTypeEmergency LeaveType = "Emergency"
 	LeaveTypeAcademic  LeaveType = "Academic"
 
-	LeaveStatusPending  LeaveStatus = "pending"
-	LeaveStatusApproved LeaveStatus = "approved"
-	LeaveStatusRejected LeaveStatus = "rejected"
+	LeaveStatusPending   LeaveStatus = "pending"
+	LeaveStatusInReview  LeaveStatus = "in_review"
+	LeaveStatusNeedsInfo LeaveStatus = "needs_info"
+	LeaveStatusApproved  LeaveStatus = "approved"
+	LeaveStatusRejected  LeaveStatus = "rejected"
+	LeaveStatusCancelled LeaveStatus = "cancelled"
+	LeaveStatusWithdrawn LeaveStatus = "withdrawn"
+
+	SLAOnTrack   SLAState = "on_track"
+	SLABreached  SLAState = "breached"
+	SLAEscalated SLAState = "escalated"
+
+	SessionFullDay   LeaveSession = "full_day"
+	SessionForenoon  LeaveSession = "forenoon"
+	SessionAfternoon LeaveSession = "afternoon"
+	SessionPeriods   LeaveSession = "periods"
 )
 
+// types that may be filed after the absence has already happened
+var RetroactiveLeaveTypes = []LeaveType{
+	LeaveTypeMedical,
+	LeaveTypeEmergency,
+}
+
+func (t LeaveType) AllowsRetroactive() bool {
+	return slices.Contains(RetroactiveLeaveTypes, t)
+}
+
 type LeaveRequest struct {
-	ID         uint        `gorm:"primaryKey" json:"id"`
-	StudentID  uint        `gorm:"index;not null" json:"student_id" binding:"required"`
-	Student    User        `gorm:"foreignKey:StudentID" json:"student,omitempty"`
-	LeaveType  LeaveType   `gorm:"type:varchar(50);not null" json:"leave_type" binding:"required"`
-	Reason     string      `gorm:"type:text;not null" json:"reason" binding:"required"`
-	StartDate  time.Time   `gorm:"not null" json:"start_date" binding:"required"`
-	EndDate    time.Time   `gorm:"not null" json:"end_date" binding:"required"`
-	Status     LeaveStatus `gorm:"type:varchar(20);default:'pending'" json:"status"`
-	ApprovedBy *uint       `gorm:"index" json:"approved_by,omitempty"`
-	Approver   *User       `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
-	Remarks    *string     `gorm:"type:text" json:"remarks,omitempty"`
-	CreatedAt  time.Time   `json:"created_at"`
-	UpdatedAt  time.Time   `json:"updated_at"`
-}
-
-//checks if leave dates are valid
+	ID                 uint                `gorm:"primaryKey" json:"id"`
+	StudentID          uint                `gorm:"index;not null" json:"student_id" binding:"required"`
+	Student            User                `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	LeaveType          LeaveType           `gorm:"type:varchar(50);not null" json:"leave_type" binding:"required"`
+	Reason             string              `gorm:"type:text;not null" json:"reason" binding:"required"`
+	StartDate          time.Time           `gorm:"not null" json:"start_date" binding:"required"`
+	EndDate            time.Time           `gorm:"not null" json:"end_date" binding:"required"`
+	Session            LeaveSession        `gorm:"type:varchar(20);default:'full_day'" json:"session"`
+	StartPeriod        int                 `gorm:"default:0" json:"start_period,omitempty"`
+	EndPeriod          int                 `gorm:"default:0" json:"end_period,omitempty"`
+	Days               float64             `gorm:"not null;default:0" json:"days"`
+	Status             LeaveStatus         `gorm:"type:varchar(20);default:'pending'" json:"status"`
+	ApprovedBy         *uint               `gorm:"index" json:"approved_by,omitempty"`
+	Approver           *User               `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
+	OnBehalfOfID       *uint               `gorm:"index" json:"on_behalf_of_id,omitempty"`
+	OnBehalfOf         *User               `gorm:"foreignKey:OnBehalfOfID" json:"on_behalf_of,omitempty"`
+	Remarks            *string             `gorm:"type:text" json:"remarks,omitempty"`
+	ParentID           *uint               `gorm:"index" json:"parent_id,omitempty"`
+	Recurrence         RecurrenceFrequency `gorm:"type:varchar(20)" json:"recurrence,omitempty"`
+	RecurrenceInterval int                 `gorm:"default:0" json:"recurrence_interval,omitempty"`
+	RecurrenceUntil    *time.Time          `json:"recurrence_until,omitempty"`
+	Occurrences        []LeaveOccurrence   `gorm:"foreignKey:LeaveID" json:"occurrences,omitempty"`
+	Retroactive        bool                `gorm:"default:false" json:"retroactive"`
+	CurrentStep        int                 `gorm:"default:1" json:"current_step"`
+	AwaitingRole       Role                `gorm:"type:varchar(20);index" json:"awaiting_role,omitempty"`
+	AssignedApproverID *uint               `gorm:"index" json:"assigned_approver_id,omitempty"`
+	Approvals          []LeaveApproval     `gorm:"foreignKey:LeaveID" json:"approvals,omitempty"`
+	DueAt              *time.Time          `gorm:"index" json:"due_at,omitempty"`
+	Escalated          bool                `gorm:"default:false" json:"escalated"`
+	EscalatedAt        *time.Time          `json:"escalated_at,omitempty"`
+	SLAState           SLAState            `gorm:"-" json:"sla_state,omitempty"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt      `gorm:"index" json:"-"`
+}
+
+// statuses that no longer hold the dates
+var InactiveLeaveStatuses = []LeaveStatus{
+	LeaveStatusRejected,
+	LeaveStatusCancelled,
+	LeaveStatusWithdrawn,
+}
+
+// extensions are child requests that push out their parent's end date
+func (l *LeaveRequest) IsExtension() bool {
+	return l.ParentID != nil
+}
+
+// the approver whose authority decided the request, which is the delegator when a delegate acted
+func (l *LeaveRequest) DecidedFor() *uint {
+	if l.OnBehalfOfID != nil {
+		return l.OnBehalfOfID
+	}
+	return l.ApprovedBy
+}
+
+// where the request stands against its approval deadline
+func (l *LeaveRequest) SLAStateAt(now time.Time) SLAState {
+	switch {
+	case l.Escalated:
+		return SLAEscalated
+	case l.DueAt != nil && now.After(*l.DueAt):
+		return SLABreached
+	default:
+		return SLAOnTrack
+	}
+}
+
+// the statuses each status may move to; a request enters the machine as pending
+var leaveTransitions = map[LeaveStatus][]LeaveStatus{
+	"": {LeaveStatusPending},
+	LeaveStatusPending: {
+		LeaveStatusInReview, LeaveStatusNeedsInfo, LeaveStatusApproved, LeaveStatusRejected, LeaveStatusWithdrawn,
+	},
+	LeaveStatusInReview: {
+		LeaveStatusInReview, LeaveStatusNeedsInfo, LeaveStatusApproved, LeaveStatusRejected, LeaveStatusWithdrawn,
+	},
+	LeaveStatusNeedsInfo: {
+		LeaveStatusPending, LeaveStatusInReview, LeaveStatusApproved, LeaveStatusRejected, LeaveStatusWithdrawn,
+	},
+	LeaveStatusApproved: {LeaveStatusCancelled},
+}
+
+func (s LeaveStatus) CanTransitionTo(to LeaveStatus) bool {
+	return slices.Contains(leaveTransitions[s], to)
+}
+
+// moves the request to the status when the state machine allows it
+func (l *LeaveRequest) TransitionTo(to LeaveStatus) error {
+	if !l.Status.CanTransitionTo(to) {
+		return &InvalidTransitionError{From: l.Status, To: to}
+	}
+	l.Status = to
+	return nil
+}
+
+// pending, part-way through its approval chain, or waiting on the student's answer
+func (s LeaveStatus) IsOpen() bool {
+	return s == LeaveStatusPending || s == LeaveStatusInReview || s == LeaveStatusNeedsInfo
+}
+
+// checks the leave dates are in order; filing rules such as past dates live in the leave policy
 func (l *LeaveRequest) Validate() error {
 	if l.EndDate.Before(l.StartDate) {
 		return ErrInvalidDateRange
 	}
-	if l.StartDate.Before(time.Now().Truncate(24 * time.Hour)) {
-		return ErrPastDate
+	if !l.IsPartial() {
+		return nil
+	}
+	for _, occurrence := range l.ActiveOccurrences() {
+		if !DateOnly(occurrence.StartDate).Equal(DateOnly(occurrence.EndDate)) {
+			return ErrPartialDayRange
+		}
 	}
 	return nil
 }
+
+func (l *LeaveRequest) IsRecurring() bool {
+	return l.Recurrence != ""
+}
+
+// the spans the request takes off: its own dates, or each occurrence still standing in a series
+func (l *LeaveRequest) ActiveOccurrences() []LeaveOccurrence {
+	if !l.IsRecurring() {
+		return []LeaveOccurrence{{LeaveID: l.ID, StartDate: l.StartDate, EndDate: l.EndDate, Days: l.Days}}
+	}
+
+	var active []LeaveOccurrence
+	for _, occurrence := range l.Occurrences {
+		if !occurrence.IsCancelled() {
+			active = append(active, occurrence)
+		}
+	}
+	return active
+}
+
+// whether any active span of the request falls on the day
+func (l *LeaveRequest) CoversDate(day time.Time) bool {
+	day = DateOnly(day)
+	for _, occurrence := range l.ActiveOccurrences() {
+		if !day.Before(DateOnly(occurrence.StartDate)) && !day.After(DateOnly(occurrence.EndDate)) {
+			return true
+		}
+	}
+	return false
+}
+
+// partial-day leave covers a range of periods on a single date
+func (l *LeaveRequest) IsPartial() bool {
+	return l.StartPeriod > 0
+}
+
+// sets the periods the leave covers from its session
+func (l *LeaveRequest) SetSession(session LeaveSession, startPeriod, endPeriod, periodsPerDay int) error {
+	switch session {
+	case "", SessionFullDay:
+		l.Session = SessionFullDay
+		l.StartPeriod, l.EndPeriod = 0, 0
+		return nil
+	case SessionForenoon:
+		startPeriod, endPeriod = 1, periodsPerDay/2
+	case SessionAfternoon:
+		startPeriod, endPeriod = periodsPerDay/2+1, periodsPerDay
+	case SessionPeriods:
+		if startPeriod < 1 || endPeriod < startPeriod || endPeriod > periodsPerDay {
+			return ErrInvalidPeriodRange
+		}
+	default:
+		return ErrInvalidSession
+	}
+
+	l.Session = session
+	l.StartPeriod, l.EndPeriod = startPeriod, endPeriod
+	return nil
+}
+
+// whether the two requests claim any of the same dates and periods, comparing series occurrence by occurrence
+func (l *LeaveRequest) Overlaps(other *LeaveRequest) bool {
+	if l.IsPartial() && other.IsPartial() && (l.StartPeriod > other.EndPeriod || other.StartPeriod > l.EndPeriod) {
+		return false
+	}
+
+	for _, mine := range l.ActiveOccurrences() {
+		for _, theirs := range other.ActiveOccurrences() {
+			if !DateOnly(mine.StartDate).After(DateOnly(theirs.EndDate)) && !DateOnly(mine.EndDate).Before(DateOnly(theirs.StartDate)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LeaveSearch is a parsed leave listing query; Scopes holds what the caller may see,
+// with nil meaning every request
+type LeaveSearch struct {
+	Statuses   []LeaveStatus
+	LeaveType  LeaveType
+	StudentID  uint
+	Dept       string
+	Hostel     string
+	From       time.Time
+	To         time.Time
+	ApproverID uint
+	Text       string
+	Sort       string
+	Descending bool
+	Page       int
+	PageSize   int
+	Scopes     []ApproverScope
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+type LeaveEventAction string
+
+const (
+	LeaveEventSubmitted            LeaveEventAction = "submitted"
+	LeaveEventEdited               LeaveEventAction = "edited"
+	LeaveEventForwarded            LeaveEventAction = "forwarded"
+	LeaveEventApproved             LeaveEventAction = "approved"
+	LeaveEventAutoApproved         LeaveEventAction = "auto_approved"
+	LeaveEventRejected             LeaveEventAction = "rejected"
+	LeaveEventInfoRequested        LeaveEventAction = "info_requested"
+	LeaveEventResumed              LeaveEventAction = "resumed"
+	LeaveEventWithdrawn            LeaveEventAction = "withdrawn"
+	LeaveEventCancelled            LeaveEventAction = "cancelled"
+	LeaveEventExtended             LeaveEventAction = "extended"
+	LeaveEventEscalated            LeaveEventAction = "escalated"
+	LeaveEventDeleted              LeaveEventAction = "deleted"
+	LeaveEventOccurrencesCancelled LeaveEventAction = "occurrences_cancelled"
+	// rows filed before the history was kept, recorded in their state at the time
+	LeaveEventRecorded LeaveEventAction = "recorded"
+)
+
+// LeaveEvent is an append-only record of something that happened to a leave request;
+// ActorID is nil when the system acted on its own
+type LeaveEvent struct {
+	ID           uint             `gorm:"primaryKey" json:"id"`
+	LeaveID      uint             `gorm:"index;not null" json:"leave_id"`
+	ActorID      *uint            `gorm:"index" json:"actor_id,omitempty"`
+	Actor        *User            `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	OnBehalfOfID *uint            `gorm:"index" json:"on_behalf_of_id,omitempty"`
+	Action       LeaveEventAction `gorm:"type:varchar(30);not null" json:"action"`
+	FromStatus   LeaveStatus      `gorm:"type:varchar(20)" json:"from_status,omitempty"`
+	ToStatus     LeaveStatus      `gorm:"type:varchar(20);not null" json:"to_status"`
+	Step         int              `gorm:"default:0" json:"step,omitempty"`
+	Remarks      *string          `gorm:"type:text" json:"remarks,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLeaveRequestTransitionTo(t *testing.T) {
+	tests := []struct {
+		from   LeaveStatus
+		to     LeaveStatus
+		wantOK bool
+	}{
+		{"", LeaveStatusPending, true},
+		{"", LeaveStatusApproved, false},
+		{LeaveStatusPending, LeaveStatusInReview, true},
+		{LeaveStatusPending, LeaveStatusNeedsInfo, true},
+		{LeaveStatusPending, LeaveStatusApproved, true},
+		{LeaveStatusPending, LeaveStatusRejected, true},
+		{LeaveStatusPending, LeaveStatusWithdrawn, true},
+		{LeaveStatusPending, LeaveStatusCancelled, false},
+		{LeaveStatusInReview, LeaveStatusInReview, true},
+		{LeaveStatusInReview, LeaveStatusPending, false},
+		{LeaveStatusNeedsInfo, LeaveStatusPending, true},
+		{LeaveStatusNeedsInfo, LeaveStatusCancelled, false},
+		{LeaveStatusApproved, LeaveStatusCancelled, true},
+		{LeaveStatusApproved, LeaveStatusRejected, false},
+		{LeaveStatusApproved, LeaveStatusWithdrawn, false},
+		{LeaveStatusRejected, LeaveStatusPending, false},
+		{LeaveStatusRejected, LeaveStatusApproved, false},
+		{LeaveStatusCancelled, LeaveStatusApproved, false},
+		{LeaveStatusWithdrawn, LeaveStatusPending, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			leave := &LeaveRequest{Status: tt.from}
+			err := leave.TransitionTo(tt.to)
+
+			if tt.wantOK {
+				if err != nil {
+					t.Fatalf("TransitionTo(%q) error = %v, want nil", tt.to, err)
+				}
+				if leave.Status != tt.to {
+					t.Errorf("status = %q, want %q", leave.Status, tt.to)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrInvalidTransition) {
+				t.Fatalf("TransitionTo(%q) error = %v, want ErrInvalidTransition", tt.to, err)
+			}
+			var transitionErr *InvalidTransitionError
+			if !errors.As(err, &transitionErr) || transitionErr.From != tt.from || transitionErr.To != tt.to {
+				t.Errorf("TransitionTo(%q) error = %#v, want the attempted move", tt.to, err)
+			}
+			if leave.Status != tt.from {
+				t.Errorf("status = %q after a refused move, want it left at %q", leave.Status, tt.from)
+			}
+		})
+	}
+}
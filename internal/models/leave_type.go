@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// LeaveTypeDefinition is an admin-managed entry in the leave type catalogue
+type LeaveTypeDefinition struct {
+	ID                 uint              `gorm:"primaryKey" json:"id"`
+	Code               LeaveType         `gorm:"type:varchar(50);uniqueIndex;not null" json:"code"`
+	Name               string            `gorm:"not null" json:"name"`
+	Description        string            `gorm:"type:text" json:"description,omitempty"`
+	Active             bool              `gorm:"default:true" json:"active"`
+	DefaultQuota       *float64          `json:"default_quota,omitempty"`
+	DefaultQuotaPeriod QuotaPeriod       `gorm:"type:varchar(20);default:'annual'" json:"default_quota_period"`
+	RequiresAttachment bool              `gorm:"default:false" json:"requires_attachment"`
+	Workflow           *ApprovalWorkflow `gorm:"-" json:"workflow,omitempty"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+func (LeaveTypeDefinition) TableName() string {
+	return "leave_types"
+}
+
+// LeaveTypeStat counts the requests filed under one catalogue entry
+type LeaveTypeStat struct {
+	Code   LeaveType `json:"code"`
+	Name   string    `json:"name"`
+	Active bool      `json:"active"`
+	Count  int64     `json:"count"`
+}
+
+// seeded into an empty catalogue
+var DefaultLeaveTypes = []LeaveTypeDefinition{
+	{Code: LeaveTypeMedical, Name: "Medical", Description: "Illness, injury or medical appointments", Active: true},
+	{Code: LeaveTypePersonal, Name: "Personal", Description: "Family events and personal matters", Active: true},
+	{Code: LeaveTypeEmergency, Name: "Emergency", Description: "Urgent situations that cannot wait", Active: true},
+	{Code: LeaveTypeAcademic, Name: "Academic", Description: "Conferences, competitions and other academic activities", Active: true},
+}
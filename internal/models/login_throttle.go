@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// LoginThrottle counts recent failed sign-ins against an account email or a client address
+type LoginThrottle struct {
+	// "account:<email>" or "ip:<address>"
+	Key           string     `gorm:"primaryKey;type:varchar(320)" json:"key"`
+	Failures      int        `gorm:"not null;default:0" json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// how long sign-in stays locked, zero when it is not
+func (t *LoginThrottle) LockedFor(now time.Time) time.Duration {
+	if t.LockedUntil == nil || !now.Before(*t.LockedUntil) {
+		return 0
+	}
+	return t.LockedUntil.Sub(now)
+}
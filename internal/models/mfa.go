@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// RecoveryCodeCount is how many single-use recovery codes an enrolment hands out
+const RecoveryCodeCount = 10
+
+// RecoveryCode signs a user in once when their authenticator is unavailable; only its hash is kept
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"`
+	CodeHash  string     `gorm:"type:varchar(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MFASetup is shown once while enrolling so the secret can be added to an authenticator app
+type MFASetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFAChallenge stands in for the tokens after the password step until a code is given
+type MFAChallenge struct {
+	ChallengeToken string `json:"challenge_token"`
+	// seconds until the challenge expires
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+// MFAEnabled is returned when enrolment completes: fresh tokens plus the recovery codes, shown only now
+type MFAEnabled struct {
+	*TokenPair
+	RecoveryCodes []string `json:"recovery_codes"`
+}
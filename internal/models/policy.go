@@ -0,0 +1,60 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// LeavePolicy holds the filing rules for one leave type
+type LeavePolicy struct {
+	ID                 uint             `gorm:"primaryKey" json:"id"`
+	LeaveType          LeaveType        `gorm:"type:varchar(50);uniqueIndex;not null" json:"leave_type"`
+	MinNoticeDays      int              `gorm:"default:0" json:"min_notice_days"`
+	MaxConsecutiveDays int              `gorm:"default:0" json:"max_consecutive_days"`
+	BlockExamWeeks     bool             `gorm:"default:false" json:"block_exam_weeks"`
+	AllowedRoles       []Role           `gorm:"serializer:json;type:text" json:"allowed_roles,omitempty"`
+	AttachmentOverDays *int             `json:"attachment_required_over_days,omitempty"`
+	AllowRetroactive   bool             `gorm:"default:false" json:"allow_retroactive"`
+	RetroactiveDays    int              `gorm:"default:0" json:"retroactive_window_days"`
+	Blackouts          []PolicyBlackout `gorm:"foreignKey:PolicyID" json:"blackouts,omitempty"`
+	CreatedAt          time.Time        `json:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at"`
+}
+
+// PolicyBlackout is a window during which the leave type cannot be taken
+type PolicyBlackout struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PolicyID  uint      `gorm:"index;not null" json:"policy_id"`
+	Name      string    `gorm:"not null" json:"name"`
+	StartDate time.Time `gorm:"not null" json:"start_date"`
+	EndDate   time.Time `gorm:"not null" json:"end_date"`
+}
+
+// PolicyViolation describes one rule a leave request breaks
+type PolicyViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+const (
+	RuleRetroactive    = "retroactive"
+	RuleMinNotice      = "min_notice"
+	RuleMaxConsecutive = "max_consecutive_days"
+	RuleBlackout       = "blackout"
+	RuleExamWeek       = "exam_week"
+	RuleAllowedRoles   = "allowed_roles"
+)
+
+// an empty list lets anyone file the leave type
+func (p *LeavePolicy) AllowsRole(role Role) bool {
+	return len(p.AllowedRoles) == 0 || slices.Contains(p.AllowedRoles, role)
+}
+
+// whether the leave type needs a document for a request of the given length
+func (p *LeavePolicy) RequiresAttachment(days float64) bool {
+	return p.AttachmentOverDays != nil && days > float64(*p.AttachmentOverDays)
+}
+
+func (b *PolicyBlackout) Overlaps(start, end time.Time) bool {
+	return !DateOnly(start).After(DateOnly(b.EndDate)) && !DateOnly(end).Before(DateOnly(b.StartDate))
+}
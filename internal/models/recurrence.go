@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceMonthly RecurrenceFrequency = "monthly"
+
+	// keeps a series from materialising an unbounded number of rows
+	MaxOccurrences = 100
+)
+
+// LeaveOccurrence is one dated instance of a recurring leave series
+type LeaveOccurrence struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	LeaveID     uint       `gorm:"index;not null" json:"leave_id"`
+	Sequence    int        `gorm:"not null" json:"sequence"`
+	StartDate   time.Time  `gorm:"not null" json:"start_date"`
+	EndDate     time.Time  `gorm:"not null" json:"end_date"`
+	Days        float64    `gorm:"not null;default:0" json:"days"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (o *LeaveOccurrence) IsCancelled() bool {
+	return o.CancelledAt != nil
+}
+
+// repeats the start to end span every interval weeks or months through until; like an RRULE,
+// monthly series skip months that have no such day rather than rolling into the next one
+func ExpandRecurrence(start, end time.Time, frequency RecurrenceFrequency, interval int, until time.Time) ([]LeaveOccurrence, error) {
+	if interval < 1 {
+		interval = 1
+	}
+	if DateOnly(until).Before(DateOnly(start)) {
+		return nil, ErrInvalidRecurrence
+	}
+
+	length := end.Sub(start)
+	var occurrences []LeaveOccurrence
+	for i := 0; ; i++ {
+		var next time.Time
+		switch frequency {
+		case RecurrenceWeekly:
+			next = start.AddDate(0, 0, 7*interval*i)
+		case RecurrenceMonthly:
+			next = start.AddDate(0, interval*i, 0)
+			if next.Day() != start.Day() {
+				continue
+			}
+		default:
+			return nil, ErrInvalidRecurrence
+		}
+
+		if DateOnly(next).After(DateOnly(until)) {
+			break
+		}
+		if len(occurrences) == MaxOccurrences {
+			return nil, ErrTooManyOccurrences
+		}
+
+		occurrences = append(occurrences, LeaveOccurrence{
+			Sequence:  len(occurrences) + 1,
+			StartDate: next,
+			EndDate:   next.Add(length),
+		})
+	}
+
+	// Occurrences must not run into one another
+	if len(occurrences) > 1 && !occurrences[0].EndDate.Before(occurrences[1].StartDate) {
+		return nil, ErrInvalidRecurrence
+	}
+	return occurrences, nil
+}
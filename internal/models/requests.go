@@ -4,9 +4,32 @@ type RegisterRequest struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
-	Role     Role   `json:"role" binding:"required"`
-	Dept     string `json:"dept"`
-	Hostel   string `json:"hostel"`
+	// accounts other than students need an invitation, whose role and placement win over these
+	Role            Role   `json:"role" binding:"omitempty,oneof=student faculty warden admin security"`
+	InvitationToken string `json:"invitation_token"`
+	Dept            string `json:"dept"`
+	Hostel          string `json:"hostel"`
+	Year            int    `json:"year"`
+}
+
+type CreateInvitationRequest struct {
+	Email  string `json:"email" binding:"required,email"`
+	Role   string `json:"role" binding:"required,oneof=student faculty warden admin security"`
+	Dept   string `json:"dept"`
+	Hostel string `json:"hostel"`
+}
+
+// set by an admin or the student's warden, never by the student, since the guardian vouches for their leave
+type GuardianInput struct {
+	DateOfBirth   string `json:"date_of_birth"`
+	GuardianName  string `json:"guardian_name"`
+	GuardianEmail string `json:"guardian_email" binding:"omitempty,email"`
+	GuardianPhone string `json:"guardian_phone"`
+}
+
+type ConsentDecisionRequest struct {
+	Decision string  `json:"decision" binding:"required,oneof=granted declined"`
+	Remarks  *string `json:"remarks"`
 }
 
 type LoginRequest struct {
@@ -14,11 +37,58 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type EmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type MFACodeRequest struct {
+	// a code from the authenticator app or, where accepted, a recovery code
+	Code string `json:"code" binding:"required"`
+}
+
+type MFAVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
 type ApplyLeaveRequest struct {
-	LeaveType string `json:"leave_type" binding:"required"`
-	Reason    string `json:"reason" binding:"required"`
-	StartDate string `json:"start_date" binding:"required"`
-	EndDate   string `json:"end_date" binding:"required"`
+	LeaveType   string           `json:"leave_type" binding:"required"`
+	Reason      string           `json:"reason" binding:"required"`
+	StartDate   string           `json:"start_date" binding:"required"`
+	EndDate     string           `json:"end_date" binding:"required"`
+	Session     string           `json:"session" binding:"omitempty,oneof=full_day forenoon afternoon periods"`
+	StartPeriod int              `json:"start_period" binding:"min=0"`
+	EndPeriod   int              `json:"end_period" binding:"min=0"`
+	Recurrence  *RecurrenceInput `json:"recurrence"`
+}
+
+// repeats the leave's dates every interval weeks or months until the given date
+type RecurrenceInput struct {
+	Frequency string `json:"frequency" binding:"required,oneof=weekly monthly"`
+	Interval  int    `json:"interval" binding:"min=0"`
+	Until     string `json:"until" binding:"required"`
+}
+
+type ExtendLeaveRequest struct {
+	EndDate string `json:"end_date" binding:"required"`
+	Reason  string `json:"reason" binding:"required"`
 }
 
 type ApproveLeaveRequest struct {
@@ -26,8 +96,129 @@ type ApproveLeaveRequest struct {
 	Remarks *string `json:"remarks"`
 }
 
+type AddCommentRequest struct {
+	Body        string `json:"body" binding:"required"`
+	RequestInfo bool   `json:"request_info"`
+}
+
+// query string for GET /api/leaves; status takes a comma-separated list and
+// from/to match requests whose dates overlap the range
+type SearchLeavesRequest struct {
+	Status     string `form:"status"`
+	LeaveType  string `form:"leave_type"`
+	StudentID  uint   `form:"student_id"`
+	Dept       string `form:"dept"`
+	Hostel     string `form:"hostel"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+	ApproverID uint   `form:"approver_id"`
+	Q          string `form:"q"`
+	Sort       string `form:"sort" binding:"omitempty,oneof=created_at updated_at start_date end_date days status leave_type"`
+	Order      string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Page       int    `form:"page" binding:"min=0"`
+	PageSize   int    `form:"page_size" binding:"min=0,max=100"`
+}
+
 type MarkAttendanceRequest struct {
 	StudentID uint   `json:"student_id" binding:"required"`
 	Date      string `json:"date" binding:"required"`
 	Present   bool   `json:"present"`
+	Period    int    `json:"period" binding:"min=0"`
+}
+
+type CreateQuotaRequest struct {
+	LeaveType string  `json:"leave_type" binding:"required"`
+	Period    string  `json:"period" binding:"required,oneof=annual semester"`
+	Days      float64 `json:"days" binding:"min=0"`
+	Dept      string  `json:"dept"`
+	Year      int     `json:"year" binding:"min=0"`
+}
+
+type ApprovalStepInput struct {
+	ApproverRole string `json:"approver_role" binding:"required,oneof=faculty warden admin"`
+	HostelOnly   bool   `json:"hostel_only"`
+}
+
+type SaveWorkflowRequest struct {
+	Steps []ApprovalStepInput `json:"steps" binding:"required,min=1,dive"`
+}
+
+type CreateTermRequest struct {
+	Name      string `json:"name" binding:"required"`
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+}
+
+type CreateCalendarEventRequest struct {
+	Name      string `json:"name" binding:"required"`
+	Kind      string `json:"kind" binding:"required,oneof=holiday exam_week"`
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+}
+
+type SetWeeklyOffRequest struct {
+	Weekdays []string `json:"weekdays" binding:"required"`
+}
+
+type GatePassScanRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type CreateDelegationRequest struct {
+	DelegatorID uint   `json:"delegator_id"`
+	DelegateID  uint   `json:"delegate_id" binding:"required"`
+	StartDate   string `json:"start_date" binding:"required"`
+	EndDate     string `json:"end_date" binding:"required"`
+	Scope       string `json:"scope" binding:"omitempty,oneof=all leave_approval"`
+	Reason      string `json:"reason"`
+}
+
+type PolicyBlackoutInput struct {
+	Name      string `json:"name" yaml:"name" binding:"required"`
+	StartDate string `json:"start_date" yaml:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" yaml:"end_date" binding:"required"`
+}
+
+type SavePolicyRequest struct {
+	MinNoticeDays      int                   `json:"min_notice_days" yaml:"min_notice_days" binding:"min=0"`
+	MaxConsecutiveDays int                   `json:"max_consecutive_days" yaml:"max_consecutive_days" binding:"min=0"`
+	BlockExamWeeks     bool                  `json:"block_exam_weeks" yaml:"block_exam_weeks"`
+	AllowedRoles       []string              `json:"allowed_roles" yaml:"allowed_roles" binding:"dive,oneof=admin faculty warden student"`
+	AttachmentOverDays *int                  `json:"attachment_required_over_days" yaml:"attachment_required_over_days" binding:"omitempty,min=0"`
+	AllowRetroactive   bool                  `json:"allow_retroactive" yaml:"allow_retroactive"`
+	RetroactiveDays    int                   `json:"retroactive_window_days" yaml:"retroactive_window_days" binding:"min=0"`
+	Blackouts          []PolicyBlackoutInput `json:"blackouts" yaml:"blackouts" binding:"dive"`
+}
+
+// PolicyFile is the layout of the YAML file named by POLICY_FILE
+type PolicyFile struct {
+	Policies map[string]SavePolicyRequest `yaml:"policies"`
+}
+
+type UpdateLeaveTypeRequest struct {
+	Name               string              `json:"name" binding:"required"`
+	Description        string              `json:"description"`
+	Active             *bool               `json:"active"`
+	DefaultQuota       *float64            `json:"default_quota" binding:"omitempty,min=0"`
+	DefaultQuotaPeriod string              `json:"default_quota_period" binding:"omitempty,oneof=annual semester"`
+	RequiresAttachment bool                `json:"requires_attachment"`
+	Steps              []ApprovalStepInput `json:"approval_steps" binding:"dive"`
+}
+
+type CreateLeaveTypeRequest struct {
+	Code string `json:"code" binding:"required,max=50"`
+	UpdateLeaveTypeRequest
+}
+
+type BulkDecisionFilter struct {
+	LeaveType string `json:"leave_type"`
+	StartFrom string `json:"start_from"`
+	StartTo   string `json:"start_to"`
+}
+
+type BulkDecisionRequest struct {
+	IDs     []uint              `json:"ids" binding:"max=200"`
+	Filter  *BulkDecisionFilter `json:"filter"`
+	Status  string              `json:"status" binding:"required,oneof=approved rejected"`
+	Remarks *string             `json:"remarks"`
 }
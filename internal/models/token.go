@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// RefreshToken is stored only as a hash; each use replaces it with the next token in its family
+type RefreshToken struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	UserID    uint   `gorm:"index;not null" json:"user_id"`
+	FamilyID  string `gorm:"type:varchar(64);index;not null" json:"family_id"`
+	TokenHash string `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	// whether the session was started with a second factor, carried over on rotation
+	MFA       bool       `gorm:"not null;default:false" json:"mfa"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RevokedToken denies an access token by its jti until it would have expired anyway
+type RevokedToken struct {
+	JTI       string    `gorm:"primaryKey;type:varchar(64)" json:"jti"`
+	ExpiresAt time.Time `gorm:"index;not null" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AccountTokenPurpose string
+
+const (
+	AccountTokenEmailVerification AccountTokenPurpose = "email_verification"
+	AccountTokenPasswordReset     AccountTokenPurpose = "password_reset"
+	// issued after the password step of a login for accounts with two-factor enabled
+	AccountTokenMFAChallenge AccountTokenPurpose = "mfa_challenge"
+)
+
+// AccountToken is a single-use emailed token, stored only as a hash
+type AccountToken struct {
+	ID        uint                `gorm:"primaryKey" json:"id"`
+	UserID    uint                `gorm:"index;not null" json:"user_id"`
+	Purpose   AccountTokenPurpose `gorm:"type:varchar(30);not null" json:"purpose"`
+	TokenHash string              `gorm:"type:varchar(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt time.Time           `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time          `json:"used_at,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// TokenPair is what a client holds after signing in or refreshing
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	// seconds until the access token expires
+	ExpiresIn int64 `json:"expires_in"`
+}
@@ -9,28 +9,99 @@ import (
 type Role string
 
 const (
-	RoleAdmin   Role = "admin"
-	RoleFaculty Role = "faculty"
-	RoleWarden  Role = "warden"
-	RoleStudent Role = "student"
+	RoleAdmin    Role = "admin"
+	RoleFaculty  Role = "faculty"
+	RoleWarden   Role = "warden"
+	RoleStudent  Role = "student"
+	RoleSecurity Role = "security"
 )
 
 type User struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	Name      string         `gorm:"not null" json:"name" binding:"required"`
-	Email     string         `gorm:"uniqueIndex;not null" json:"email" binding:"required,email"`
-	Password  string         `gorm:"not null" json:"-"`
-	Role      Role           `gorm:"type:varchar(20);not null" json:"role" binding:"required"`
-	Dept      string         `gorm:"type:varchar(100)" json:"dept"`
-	Hostel    string         `gorm:"type:varchar(100)" json:"hostel,omitempty"`
-	Leaves    []LeaveRequest `gorm:"foreignKey:StudentID" json:"leaves,omitempty"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Name          string     `gorm:"not null" json:"name" binding:"required"`
+	Email         string     `gorm:"uniqueIndex;not null" json:"email" binding:"required,email"`
+	Password      string     `gorm:"not null" json:"-"`
+	Role          Role       `gorm:"type:varchar(20);not null" json:"role" binding:"required"`
+	Dept          string     `gorm:"type:varchar(100)" json:"dept"`
+	Hostel        string     `gorm:"type:varchar(100)" json:"hostel,omitempty"`
+	Year          int        `gorm:"default:0" json:"year,omitempty"`
+	DateOfBirth   *time.Time `json:"date_of_birth,omitempty"`
+	GuardianName  string     `gorm:"type:varchar(100)" json:"guardian_name,omitempty"`
+	GuardianEmail string     `gorm:"type:varchar(255)" json:"guardian_email,omitempty"`
+	GuardianPhone string     `gorm:"type:varchar(30)" json:"guardian_phone,omitempty"`
+	// set once the owner proves they receive mail at Email
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	// encrypted TOTP seed; set during enrolment, in force once MFAEnabledAt is set
+	MFASecret    string     `gorm:"type:varchar(255)" json:"-"`
+	MFAEnabledAt *time.Time `json:"mfa_enabled_at,omitempty"`
+	// last TOTP step accepted, so a code cannot be replayed within its window
+	MFALastStep int64          `gorm:"default:0" json:"-"`
+	Leaves      []LeaveRequest `gorm:"foreignKey:StudentID" json:"leaves,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
 }
 
+// ApproverScope limits which students' requests an approver sees and acts on
+type ApproverScope struct {
+	UserID uint
+	Role   Role
+	Dept   string
+	Hostel string
+}
+
+func (u *User) Scope() ApproverScope {
+	return ApproverScope{UserID: u.ID, Role: u.Role, Dept: u.Dept, Hostel: u.Hostel}
+}
+
+// a copy without date of birth and guardian contacts, for viewers with no business seeing them
+func (u *User) Public() *User {
+	public := *u
+	public.DateOfBirth = nil
+	public.GuardianName = ""
+	public.GuardianEmail = ""
+	public.GuardianPhone = ""
+	return &public
+}
+
+// faculty cover their department, wardens their hostel, admins everyone
+func (s ApproverScope) Covers(student *User) bool {
+	switch s.Role {
+	case RoleAdmin:
+		return true
+	case RoleFaculty:
+		return s.Dept != "" && s.Dept == student.Dept
+	case RoleWarden:
+		return s.Hostel != "" && s.Hostel == student.Hostel
+	default:
+		return false
+	}
+}
+
+// AgeOfMajority is the age below which a student's guardian answers for them
+const AgeOfMajority = 18
+
+// whether the user is under age on the given day; without a date of birth they are taken to be an adult
+func (u *User) IsMinorOn(day time.Time) bool {
+	if u.DateOfBirth == nil {
+		return false
+	}
+	return day.Before(u.DateOfBirth.AddDate(AgeOfMajority, 0, 0))
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsMFAEnabled() bool {
+	return u.MFAEnabledAt != nil
+}
+
+// bcrypt work factor for new hashes; older hashes are upgraded on the next sign-in
+const PasswordCost = 12
+
 // creates salted hash
 func (u *User) HashPassword(password string) error {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), PasswordCost)
 	if err != nil {
 		return err
 	}
@@ -38,6 +109,12 @@ func (u *User) HashPassword(password string) error {
 	return nil
 }
 
+// whether the stored hash was made with a different work factor
+func (u *User) NeedsRehash() bool {
+	cost, err := bcrypt.Cost([]byte(u.Password))
+	return err != nil || cost != PasswordCost
+}
+
 // checks input password with correct password
 func (u *User) CheckPassword(password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
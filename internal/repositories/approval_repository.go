@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+)
+
+type ApprovalRepository struct {
+	db *gorm.DB
+}
+
+func NewApprovalRepository(db *gorm.DB) *ApprovalRepository {
+	return &ApprovalRepository{db: db}
+}
+
+func (r *ApprovalRepository) WithTx(tx *gorm.DB) *ApprovalRepository {
+	return &ApprovalRepository{db: tx}
+}
+
+func (r *ApprovalRepository) FindWorkflows() ([]models.ApprovalWorkflow, error) {
+	var workflows []models.ApprovalWorkflow
+	err := r.db.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("step_order ASC")
+	}).Order("leave_type ASC").Find(&workflows).Error
+	return workflows, err
+}
+
+// returns nil without error when the leave type has no workflow
+func (r *ApprovalRepository) FindWorkflowByType(leaveType models.LeaveType) (*models.ApprovalWorkflow, error) {
+	var workflow models.ApprovalWorkflow
+	err := r.db.Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("step_order ASC")
+	}).Where("leave_type = ?", leaveType).First(&workflow).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+// creates the workflow or replaces the steps of the existing one
+func (r *ApprovalRepository) SaveWorkflow(workflow *models.ApprovalWorkflow) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.ApprovalWorkflow
+		err := tx.Where("leave_type = ?", workflow.LeaveType).First(&existing).Error
+		switch {
+		case err == nil:
+			workflow.ID = existing.ID
+			workflow.CreatedAt = existing.CreatedAt
+			if err := tx.Where("workflow_id = ?", existing.ID).Delete(&models.ApprovalStep{}).Error; err != nil {
+				return err
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		return tx.Save(workflow).Error
+	})
+}
+
+func (r *ApprovalRepository) DeleteWorkflow(leaveType models.LeaveType) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var workflow models.ApprovalWorkflow
+		if err := tx.Where("leave_type = ?", leaveType).First(&workflow).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrWorkflowNotFound
+			}
+			return err
+		}
+
+		if err := tx.Where("workflow_id = ?", workflow.ID).Delete(&models.ApprovalStep{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&workflow).Error
+	})
+}
+
+func (r *ApprovalRepository) CreateApproval(approval *models.LeaveApproval) error {
+	return r.db.Create(approval).Error
+}
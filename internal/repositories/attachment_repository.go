@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) Create(attachment *models.LeaveAttachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *AttachmentRepository) FindByID(id uint) (*models.LeaveAttachment, error) {
+	var attachment models.LeaveAttachment
+	err := r.db.First(&attachment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *AttachmentRepository) FindByLeaveID(leaveID uint) ([]models.LeaveAttachment, error) {
+	var attachments []models.LeaveAttachment
+	err := r.db.Where("leave_id = ?", leaveID).
+		Order("created_at ASC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *AttachmentRepository) CountByLeaveID(leaveID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.LeaveAttachment{}).Where("leave_id = ?", leaveID).Count(&count).Error
+	return count, err
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/prannvs/campus-leave-system/internal/models"
@@ -15,13 +16,19 @@ func NewAttendanceRepository(db *gorm.DB) *AttendanceRepository {
 	return &AttendanceRepository{db: db}
 }
 
+func (r *AttendanceRepository) WithTx(tx *gorm.DB) *AttendanceRepository {
+	return &AttendanceRepository{db: tx}
+}
+
 func (r *AttendanceRepository) Create(attendance *models.Attendance) error {
 	return r.db.Create(attendance).Error
 }
 
-func (r *AttendanceRepository) FindByStudentAndDate(studentID uint, date time.Time) (*models.Attendance, error) {
+// finds a row that clashes with marking the given period, where period zero is the whole day
+func (r *AttendanceRepository) FindByStudentAndDate(studentID uint, date time.Time, period int) (*models.Attendance, error) {
 	var attendance models.Attendance
 	err := r.db.Where("student_id = ? AND DATE(date) = DATE(?)", studentID, date).
+		Where("period = 0 OR ? = 0 OR period = ?", period, period).
 		First(&attendance).Error
 	if err != nil {
 		return nil, err
@@ -29,57 +36,124 @@ func (r *AttendanceRepository) FindByStudentAndDate(studentID uint, date time.Ti
 	return &attendance, nil
 }
 
-func (r *AttendanceRepository) GetStats(studentID uint, startDate, endDate time.Time) (*models.AttendanceStats, error) {
-	var presentDays, totalDays int64
+// weighs a whole-day row as one day and a period row as its share of the day
+func dayWeight(periodsPerDay int, alias string) string {
+	return fmt.Sprintf("CASE WHEN %speriod = 0 THEN 1.0 ELSE 1.0 / %d END", alias, periodsPerDay)
+}
+
+// excluded lists non-working dates that must not count towards the totals
+func (r *AttendanceRepository) GetStats(studentID uint, startDate, endDate time.Time, excluded []time.Time, periodsPerDay int) (*models.AttendanceStats, error) {
+	var presentDays, excusedDays, totalDays float64
+	weight := "COALESCE(SUM(" + dayWeight(periodsPerDay, "") + "), 0)"
+
+	base := func() *gorm.DB {
+		query := r.db.Model(&models.Attendance{}).
+			Select(weight).
+			Where("student_id = ? AND date BETWEEN ? AND ?", studentID, startDate, endDate)
+		if len(excluded) > 0 {
+			query = query.Where("DATE(date) NOT IN ?", formatDates(excluded))
+		}
+		return query
+	}
 
 	// Count total days
-	r.db.Model(&models.Attendance{}).
-		Where("student_id = ? AND date BETWEEN ? AND ?", studentID, startDate, endDate).
-		Count(&totalDays)
+	base().Scan(&totalDays)
 
 	// Count present days
-	r.db.Model(&models.Attendance{}).
-		Where("student_id = ? AND date BETWEEN ? AND ? AND present = ?", studentID, startDate, endDate, true).
-		Count(&presentDays)
+	base().Where("present = ?", true).Scan(&presentDays)
+
+	// Count absences covered by approved leave
+	base().Where("present = ? AND leave_id IS NOT NULL", false).Scan(&excusedDays)
 
 	percentage := 0.0
 	if totalDays > 0 {
-		percentage = (float64(presentDays) / float64(totalDays)) * 100
+		percentage = (presentDays / totalDays) * 100
 	}
 
 	return &models.AttendanceStats{
 		StudentID:            studentID,
 		PresentDays:          presentDays,
+		ExcusedDays:          excusedDays,
 		TotalDays:            totalDays,
 		AttendancePercentage: percentage,
 	}, nil
 }
 
-func (r *AttendanceRepository) GetLowAttendanceStudents(threshold float64, startDate, endDate time.Time) ([]map[string]interface{}, error) {
+func (r *AttendanceRepository) GetLowAttendanceStudents(threshold float64, startDate, endDate time.Time, excluded []time.Time, periodsPerDay int) ([]map[string]interface{}, error) {
 	var results []map[string]interface{}
 
+	args := []interface{}{startDate, endDate}
+	exclusion := ""
+	if len(excluded) > 0 {
+		exclusion = "AND DATE(a.date) NOT IN ?"
+		args = append(args, formatDates(excluded))
+	}
+	args = append(args, threshold)
+
+	weight := dayWeight(periodsPerDay, "a.")
 	query := `
 		SELECT 
 			u.id as student_id,
 			u.name as student_name,
 			u.dept,
-			COUNT(*) as total_days,
-			SUM(CASE WHEN a.present THEN 1 ELSE 0 END) as present_days,
-			(SUM(CASE WHEN a.present THEN 1 ELSE 0 END)::float / COUNT(*)::float * 100) as attendance_percentage
+			SUM(` + weight + `) as total_days,
+			SUM(CASE WHEN a.present THEN ` + weight + ` ELSE 0 END) as present_days,
+			(SUM(CASE WHEN a.present THEN ` + weight + ` ELSE 0 END) / SUM(` + weight + `) * 100) as attendance_percentage
 		FROM users u
 		INNER JOIN attendances a ON u.id = a.student_id
 		WHERE u.role = 'student' 
 			AND a.date BETWEEN ? AND ?
+			` + exclusion + `
 		GROUP BY u.id, u.name, u.dept
-		HAVING (SUM(CASE WHEN a.present THEN 1 ELSE 0 END)::float / COUNT(*)::float * 100) < ?
+		HAVING (SUM(CASE WHEN a.present THEN ` + weight + ` ELSE 0 END) / SUM(` + weight + `) * 100) < ?
 		ORDER BY attendance_percentage ASC
 		LIMIT 10
 	`
 
-	err := r.db.Raw(query, startDate, endDate, threshold).Scan(&results).Error
+	err := r.db.Raw(query, args...).Scan(&results).Error
 	return results, err
 }
 
 func (r *AttendanceRepository) BulkCreate(attendances []models.Attendance) error {
 	return r.db.Create(&attendances).Error
 }
+
+// links the student's recorded absences on the given dates to the leave; when it covers part of a
+// day that is the absences in its periods plus any whole-day absence, which the leave partly explains
+func (r *AttendanceRepository) ExcuseAbsences(leave *models.LeaveRequest, dates []time.Time) error {
+	query := r.db.Model(&models.Attendance{}).
+		Where("student_id = ? AND present = ? AND leave_id IS NULL", leave.StudentID, false).
+		Where("DATE(date) IN ?", formatDates(dates))
+	if leave.IsPartial() {
+		query = query.Where("period = 0 OR period BETWEEN ? AND ?", leave.StartPeriod, leave.EndPeriod)
+	}
+
+	return query.Updates(map[string]interface{}{"leave_id": leave.ID, "regularised": true}).Error
+}
+
+// removes the rows generated for an approved leave and restores the absences it excused
+func (r *AttendanceRepository) ReleaseByLeave(leaveID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Attendance{}).
+			Where("leave_id = ? AND regularised = ?", leaveID, true).
+			Updates(map[string]interface{}{"leave_id": nil, "regularised": false}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("leave_id = ?", leaveID).Delete(&models.Attendance{}).Error
+	})
+}
+
+// removes the rows an approved leave generated on the given dates, as when one occurrence of a series is cancelled
+func (r *AttendanceRepository) ReleaseByLeaveDates(leaveID uint, dates []time.Time) error {
+	return r.db.Where("leave_id = ? AND DATE(date) IN ?", leaveID, formatDates(dates)).
+		Delete(&models.Attendance{}).Error
+}
+
+func formatDates(dates []time.Time) []string {
+	formatted := make([]string, 0, len(dates))
+	for _, date := range dates {
+		formatted = append(formatted, date.Format("2006-01-02"))
+	}
+	return formatted
+}
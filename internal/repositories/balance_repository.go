@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BalanceRepository struct {
+	db *gorm.DB
+}
+
+func NewBalanceRepository(db *gorm.DB) *BalanceRepository {
+	return &BalanceRepository{db: db}
+}
+
+func (r *BalanceRepository) WithTx(tx *gorm.DB) *BalanceRepository {
+	return &BalanceRepository{db: tx}
+}
+
+func (r *BalanceRepository) CreateQuota(quota *models.LeaveQuota) error {
+	return r.db.Create(quota).Error
+}
+
+func (r *BalanceRepository) FindQuotas() ([]models.LeaveQuota, error) {
+	var quotas []models.LeaveQuota
+	err := r.db.Order("leave_type ASC, id ASC").Find(&quotas).Error
+	return quotas, err
+}
+
+func (r *BalanceRepository) FindQuotasByType(leaveType models.LeaveType) ([]models.LeaveQuota, error) {
+	var quotas []models.LeaveQuota
+	err := r.db.Where("leave_type = ?", leaveType).Find(&quotas).Error
+	return quotas, err
+}
+
+func (r *BalanceRepository) DeleteQuota(id uint) error {
+	result := r.db.Delete(&models.LeaveQuota{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrQuotaNotFound
+	}
+	return nil
+}
+
+func (r *BalanceRepository) CreateEntry(entry *models.LeaveLedgerEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// net days debited for a leave type within the period
+func (r *BalanceRepository) SumUsed(studentID uint, leaveType models.LeaveType, startDate, endDate time.Time) (float64, error) {
+	var used float64
+	err := r.db.Model(&models.LeaveLedgerEntry{}).
+		Select("COALESCE(SUM(CASE WHEN entry_type = ? THEN days ELSE -days END), 0)", models.LedgerEntryDebit).
+		Where("student_id = ? AND leave_type = ? AND date BETWEEN ? AND ?", studentID, leaveType, startDate, endDate).
+		Scan(&used).Error
+	return used, err
+}
+
+// holds the student's row until the surrounding transaction ends, so approvals for the same
+// student spend their quota one at a time
+func (r *BalanceRepository) LockStudent(studentID uint) error {
+	return r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&models.User{}, studentID).Error
+}
+
+// what is still debited against the leave, one entry per ledger date
+func (r *BalanceRepository) NetByDate(leaveID uint) ([]models.LeaveLedgerEntry, error) {
+	var entries []models.LeaveLedgerEntry
+	err := r.db.Model(&models.LeaveLedgerEntry{}).
+		Select("date, SUM(CASE WHEN entry_type = ? THEN days ELSE -days END) AS days", models.LedgerEntryDebit).
+		Where("leave_id = ?", leaveID).
+		Group("date").
+		Having("SUM(CASE WHEN entry_type = ? THEN days ELSE -days END) > 0", models.LedgerEntryDebit).
+		Order("date ASC").
+		Scan(&entries).Error
+	return entries, err
+}
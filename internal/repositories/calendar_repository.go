@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+)
+
+type CalendarRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) *CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+func (r *CalendarRepository) CreateTerm(term *models.AcademicTerm) error {
+	return r.db.Create(term).Error
+}
+
+func (r *CalendarRepository) FindTerms() ([]models.AcademicTerm, error) {
+	var terms []models.AcademicTerm
+	err := r.db.Order("start_date ASC").Find(&terms).Error
+	return terms, err
+}
+
+func (r *CalendarRepository) DeleteTerm(id uint) error {
+	result := r.db.Delete(&models.AcademicTerm{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrCalendarEntryNotFound
+	}
+	return nil
+}
+
+func (r *CalendarRepository) CreateEvent(event *models.CalendarEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *CalendarRepository) FindEvents() ([]models.CalendarEvent, error) {
+	var events []models.CalendarEvent
+	err := r.db.Order("start_date ASC").Find(&events).Error
+	return events, err
+}
+
+// events that overlap the given range
+func (r *CalendarRepository) FindEventsBetween(startDate, endDate time.Time) ([]models.CalendarEvent, error) {
+	var events []models.CalendarEvent
+	err := r.db.Where("start_date <= ? AND end_date >= ?", endDate, startDate).
+		Order("start_date ASC").
+		Find(&events).Error
+	return events, err
+}
+
+func (r *CalendarRepository) DeleteEvent(id uint) error {
+	result := r.db.Delete(&models.CalendarEvent{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrCalendarEntryNotFound
+	}
+	return nil
+}
+
+func (r *CalendarRepository) FindWeeklyOffs() ([]models.WeeklyOff, error) {
+	var offs []models.WeeklyOff
+	err := r.db.Order("weekday ASC").Find(&offs).Error
+	return offs, err
+}
+
+func (r *CalendarRepository) ReplaceWeeklyOffs(offs []models.WeeklyOff) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.WeeklyOff{}).Error; err != nil {
+			return err
+		}
+		if len(offs) == 0 {
+			return nil
+		}
+		return tx.Create(&offs).Error
+	})
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+func (r *CommentRepository) Create(comment *models.LeaveComment) error {
+	if err := r.db.Create(comment).Error; err != nil {
+		return err
+	}
+	return r.db.Preload("Author").First(comment, comment.ID).Error
+}
+
+func (r *CommentRepository) FindByLeaveID(leaveID uint) ([]models.LeaveComment, error) {
+	var comments []models.LeaveComment
+	err := r.db.Where("leave_id = ?", leaveID).
+		Preload("Author").
+		Order("created_at ASC").
+		Find(&comments).Error
+	return comments, err
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+)
+
+type ConsentRepository struct {
+	db *gorm.DB
+}
+
+func NewConsentRepository(db *gorm.DB) *ConsentRepository {
+	return &ConsentRepository{db: db}
+}
+
+func (r *ConsentRepository) Create(consent *models.GuardianConsent) error {
+	return r.db.Create(consent).Error
+}
+
+func (r *ConsentRepository) FindByID(id uint) (*models.GuardianConsent, error) {
+	var consent models.GuardianConsent
+	err := r.db.Preload("Leave").Preload("Leave.Student").First(&consent, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+// returns nil without error when no consent has been asked for the leave
+func (r *ConsentRepository) FindByLeaveID(leaveID uint) (*models.GuardianConsent, error) {
+	var consent models.GuardianConsent
+	err := r.db.Where("leave_id = ?", leaveID).First(&consent).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+// whether a guardian is still being asked about any open leave of the student
+func (r *ConsentRepository) HasPendingForStudent(studentID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.GuardianConsent{}).
+		Joins("JOIN leave_requests l ON l.id = guardian_consents.leave_id").
+		Where("l.student_id = ? AND l.deleted_at IS NULL AND l.status IN ?", studentID,
+			[]models.LeaveStatus{models.LeaveStatusPending, models.LeaveStatusInReview, models.LeaveStatusNeedsInfo}).
+		Where("guardian_consents.status = ?", models.ConsentPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// records the guardian's answer only while the consent is pending behind the same link, so a second
+// click finds nothing to update
+func (r *ConsentRepository) Respond(consent *models.GuardianConsent, nonce string) (bool, error) {
+	result := r.db.Model(&models.GuardianConsent{}).
+		Where("id = ? AND status = ? AND nonce = ?", consent.ID, models.ConsentPending, nonce).
+		Updates(map[string]interface{}{
+			"status":       consent.Status,
+			"remarks":      consent.Remarks,
+			"responded_at": consent.RespondedAt,
+			"nonce":        "",
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *ConsentRepository) Update(consent *models.GuardianConsent) error {
+	return r.db.Omit("Leave").Save(consent).Error
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+)
+
+type DelegationRepository struct {
+	db *gorm.DB
+}
+
+func NewDelegationRepository(db *gorm.DB) *DelegationRepository {
+	return &DelegationRepository{db: db}
+}
+
+func (r *DelegationRepository) Create(delegation *models.Delegation) error {
+	return r.db.Create(delegation).Error
+}
+
+func (r *DelegationRepository) FindByID(id uint) (*models.Delegation, error) {
+	var delegation models.Delegation
+	err := r.db.Preload("Delegator").Preload("Delegate").First(&delegation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delegation, nil
+}
+
+// delegations the user has given or received, or every delegation when userID is zero
+func (r *DelegationRepository) FindForUser(userID uint) ([]models.Delegation, error) {
+	var delegations []models.Delegation
+	query := r.db.Preload("Delegator").Preload("Delegate")
+	if userID != 0 {
+		query = query.Where("delegator_id = ? OR delegate_id = ?", userID, userID)
+	}
+
+	err := query.Order("start_date DESC").Find(&delegations).Error
+	return delegations, err
+}
+
+// unrevoked delegations to the user whose date range includes the given day
+func (r *DelegationRepository) FindActiveForDelegate(delegateID uint, day time.Time) ([]models.Delegation, error) {
+	var delegations []models.Delegation
+	err := r.db.Preload("Delegator").
+		Where("delegate_id = ? AND revoked_at IS NULL", delegateID).
+		Where("start_date <= ? AND end_date >= ?", day, day).
+		Find(&delegations).Error
+	return delegations, err
+}
+
+func (r *DelegationRepository) Update(delegation *models.Delegation) error {
+	return r.db.Omit("Delegator", "Delegate").Save(delegation).Error
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+)
+
+type GatePassRepository struct {
+	db *gorm.DB
+}
+
+func NewGatePassRepository(db *gorm.DB) *GatePassRepository {
+	return &GatePassRepository{db: db}
+}
+
+func (r *GatePassRepository) WithTx(tx *gorm.DB) *GatePassRepository {
+	return &GatePassRepository{db: tx}
+}
+
+func (r *GatePassRepository) Create(pass *models.GatePass) error {
+	return r.db.Create(pass).Error
+}
+
+func (r *GatePassRepository) FindByCode(code string) (*models.GatePass, error) {
+	var pass models.GatePass
+	err := r.db.Preload("Leave").Preload("Student").
+		Where("code = ?", code).
+		First(&pass).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pass, nil
+}
+
+func (r *GatePassRepository) FindByLeaveID(leaveID uint) (*models.GatePass, error) {
+	var pass models.GatePass
+	err := r.db.Where("leave_id = ?", leaveID).First(&pass).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pass, nil
+}
+
+// passes of students currently outside, optionally limited to one hostel
+func (r *GatePassRepository) FindOut(hostel string) ([]models.GatePass, error) {
+	var passes []models.GatePass
+	query := r.db.Where("status = ?", models.GatePassOut)
+	if hostel != "" {
+		query = query.Where("hostel = ?", hostel)
+	}
+
+	err := query.Preload("Leave").Preload("Student").
+		Order("checked_out_at ASC").
+		Find(&passes).Error
+	return passes, err
+}
+
+func (r *GatePassRepository) Update(pass *models.GatePass) error {
+	return r.db.Omit("Leave", "Student").Save(pass).Error
+}
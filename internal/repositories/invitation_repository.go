@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+)
+
+type InvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) *InvitationRepository {
+	return &InvitationRepository{db: db}
+}
+
+func (r *InvitationRepository) Create(invitation *models.Invitation) error {
+	return r.db.Create(invitation).Error
+}
+
+func (r *InvitationRepository) FindByID(id uint) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.db.First(&invitation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *InvitationRepository) FindAll() ([]models.Invitation, error) {
+	var invitations []models.Invitation
+	err := r.db.Preload("InvitedBy").
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *InvitationRepository) Update(invitation *models.Invitation) error {
+	return r.db.Omit("InvitedBy").Save(invitation).Error
+}
+
+// marks the invitation used, failing when another registration got to it first
+func (r *InvitationRepository) Claim(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", id, now).
+		Update("accepted_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+// hands a claimed invitation back when the account could not be created
+func (r *InvitationRepository) Release(id uint) error {
+	return r.db.Model(&models.Invitation{}).Where("id = ?", id).Update("accepted_at", nil).Error
+}
+
+func (r *InvitationRepository) SetAcceptedUser(id, userID uint) error {
+	return r.db.Model(&models.Invitation{}).Where("id = ?", id).Update("accepted_user_id", userID).Error
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+)
+
+// LeaveEventRepository only ever appends; the history is never edited or removed
+type LeaveEventRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveEventRepository(db *gorm.DB) *LeaveEventRepository {
+	return &LeaveEventRepository{db: db}
+}
+
+func (r *LeaveEventRepository) WithTx(tx *gorm.DB) *LeaveEventRepository {
+	return &LeaveEventRepository{db: tx}
+}
+
+func (r *LeaveEventRepository) Create(event *models.LeaveEvent) error {
+	return r.db.Create(event).Error
+}
+
+func (r *LeaveEventRepository) FindByLeaveID(leaveID uint) ([]models.LeaveEvent, error) {
+	var events []models.LeaveEvent
+	err := r.db.Where("leave_id = ?", leaveID).
+		Preload("Actor").
+		Order("created_at ASC, id ASC").
+		Find(&events).Error
+	return events, err
+}
@@ -1,12 +1,17 @@
 package repositories
 
 import (
+	"strings"
 	"time"
 
 	"github.com/prannvs/campus-leave-system/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// escapes LIKE wildcards in user-supplied search text
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type LeaveRepository struct {
 	db *gorm.DB
 }
@@ -15,6 +20,21 @@ func NewLeaveRepository(db *gorm.DB) *LeaveRepository {
 	return &LeaveRepository{db: db}
 }
 
+func (r *LeaveRepository) WithTx(tx *gorm.DB) *LeaveRepository {
+	return &LeaveRepository{db: tx}
+}
+
+func (r *LeaveRepository) Transaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
+// holds the request's row until the surrounding transaction ends
+func (r *LeaveRepository) Lock(id uint) error {
+	return r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&models.LeaveRequest{}, id).Error
+}
+
 // internal/repositories/leave_repository.go
 
 func (r *LeaveRepository) Create(leave *models.LeaveRequest) error {
@@ -31,7 +51,15 @@ func (r *LeaveRepository) Create(leave *models.LeaveRequest) error {
 
 func (r *LeaveRepository) FindByID(id uint) (*models.LeaveRequest, error) {
 	var leave models.LeaveRequest
-	err := r.db.Preload("Student").Preload("Approver").First(&leave, id).Error
+	err := r.db.Preload("Student").Preload("Approver").Preload("OnBehalfOf").
+		Preload("Approvals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Approvals.Approver").
+		Preload("Occurrences", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sequence ASC")
+		}).
+		First(&leave, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -43,84 +71,217 @@ func (r *LeaveRepository) FindByStudentID(studentID uint) ([]models.LeaveRequest
 	err := r.db.Where("student_id = ?", studentID).
 		Preload("Student"). // ← Add this line!
 		Preload("Approver").
+		Preload("Occurrences", func(db *gorm.DB) *gorm.DB {
+			return db.Order("sequence ASC")
+		}).
 		Order("created_at DESC").
 		Find(&leaves).Error
 	return leaves, err
 }
 
-func (r *LeaveRepository) FindPending() ([]models.LeaveRequest, error) {
+// open requests waiting on the scope's role from students it covers, plus any assigned to the approver directly
+func (r *LeaveRepository) FindPending(scopes []models.ApproverScope) ([]models.LeaveRequest, error) {
 	var leaves []models.LeaveRequest
-	err := r.db.Where("status = ?", models.LeaveStatusPending).
-		Preload("Student").
+	query := r.db.Where("status IN ?", []models.LeaveStatus{models.LeaveStatusPending, models.LeaveStatusInReview})
+
+	var filter *gorm.DB
+	for _, scope := range scopes {
+		if scope.Role == models.RoleAdmin {
+			filter = nil
+			break
+		}
+
+		cond := r.db.Where("assigned_approver_id = ?", scope.UserID)
+		if students := r.scopedStudents(scope); students != nil {
+			cond = cond.Or("assigned_approver_id IS NULL AND awaiting_role = ? AND student_id IN (?)", scope.Role, students)
+		}
+
+		if filter == nil {
+			filter = cond
+		} else {
+			filter = filter.Or(cond)
+		}
+	}
+	if filter != nil {
+		query = query.Where(filter)
+	}
+
+	err := query.Preload("Student").
+		Preload("Approvals").
 		Order("created_at ASC").
 		Find(&leaves).Error
 	return leaves, err
 }
 
-func (r *LeaveRepository) FindByStatus(status models.LeaveStatus, page, pageSize int) ([]models.LeaveRequest, int64, error) {
+// subquery of student IDs the scope covers, nil when it covers nobody
+func (r *LeaveRepository) scopedStudents(scope models.ApproverScope) *gorm.DB {
+	switch scope.Role {
+	case models.RoleFaculty:
+		return r.db.Model(&models.User{}).Select("id").Where("dept = ?", scope.Dept)
+	case models.RoleWarden:
+		return r.db.Model(&models.User{}).Select("id").Where("hostel = ?", scope.Hostel)
+	default:
+		return nil
+	}
+}
+
+// open requests past their deadline that have not been escalated yet
+func (r *LeaveRepository) FindOverdue(now time.Time) ([]models.LeaveRequest, error) {
+	var leaves []models.LeaveRequest
+	err := r.db.Where("status IN ?", []models.LeaveStatus{models.LeaveStatusPending, models.LeaveStatusInReview}).
+		Where("escalated = ? AND due_at < ?", false, now).
+		Preload("Student").
+		Order("due_at ASC").
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// extensions filed against a leave that still hold their dates
+func (r *LeaveRepository) FindActiveExtensions(parentID uint) ([]models.LeaveRequest, error) {
+	var leaves []models.LeaveRequest
+	err := r.db.Where("parent_id = ? AND status NOT IN ?", parentID, models.InactiveLeaveStatuses).
+		Order("start_date ASC").
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// filters, sorts and pages the requests the search's scopes can see
+func (r *LeaveRepository) Search(search models.LeaveSearch) ([]models.LeaveRequest, int64, error) {
 	var leaves []models.LeaveRequest
 	var total int64
 
-	offset := (page - 1) * pageSize
+	query := r.db.Model(&models.LeaveRequest{})
 
-	query := r.db.Model(&models.LeaveRequest{}).Where("status = ?", status)
+	if len(search.Statuses) > 0 {
+		query = query.Where("status IN ?", search.Statuses)
+	}
+	if search.LeaveType != "" {
+		query = query.Where("leave_type = ?", search.LeaveType)
+	}
+	if search.StudentID != 0 {
+		query = query.Where("student_id = ?", search.StudentID)
+	}
+	if search.Dept != "" {
+		query = query.Where("student_id IN (?)", r.db.Model(&models.User{}).Select("id").Where("dept = ?", search.Dept))
+	}
+	if search.Hostel != "" {
+		query = query.Where("student_id IN (?)", r.db.Model(&models.User{}).Select("id").Where("hostel = ?", search.Hostel))
+	}
+	if !search.From.IsZero() {
+		query = query.Where("end_date >= ?", search.From)
+	}
+	if !search.To.IsZero() {
+		query = query.Where("start_date <= ?", search.To)
+	}
+	if search.ApproverID != 0 {
+		query = query.Where(
+			r.db.Where("approved_by = ? OR on_behalf_of_id = ?", search.ApproverID, search.ApproverID).
+				Or("id IN (?)", r.db.Model(&models.LeaveApproval{}).Select("leave_id").Where("approver_id = ?", search.ApproverID)),
+		)
+	}
+	if search.Text != "" {
+		query = query.Where("reason ILIKE ?", "%"+likeEscaper.Replace(search.Text)+"%")
+	}
+	if visible := r.visibleTo(search.Scopes); visible != nil {
+		query = query.Where(visible)
+	}
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := query.Preload("Student").Preload("Approver").
-		Offset(offset).Limit(pageSize).
-		Order("created_at DESC").
+	err := query.Preload("Student").Preload("Approver").Preload("Occurrences").
+		Order(clause.OrderByColumn{Column: clause.Column{Name: search.Sort}, Desc: search.Descending}).
+		Order("id DESC").
+		Offset((search.Page - 1) * search.PageSize).Limit(search.PageSize).
 		Find(&leaves).Error
 
 	return leaves, total, err
 }
 
-func (r *LeaveRepository) Update(leave *models.LeaveRequest) error {
-	return r.db.Save(leave).Error
+// requests any of the approver scopes covers or has acted on, nil when one of them sees everything
+func (r *LeaveRepository) visibleTo(scopes []models.ApproverScope) *gorm.DB {
+	if scopes == nil {
+		return nil
+	}
+
+	// No scopes at all means nothing is visible
+	filter := r.db.Where("1 = 0")
+	for _, scope := range scopes {
+		if scope.Role == models.RoleAdmin {
+			return nil
+		}
+
+		filter = filter.Or("assigned_approver_id = ? OR approved_by = ? OR on_behalf_of_id = ?", scope.UserID, scope.UserID, scope.UserID)
+		if students := r.scopedStudents(scope); students != nil {
+			filter = filter.Or("student_id IN (?)", students)
+		}
+	}
+	return filter
 }
 
-func (r *LeaveRepository) Delete(id uint) error {
-	return r.db.Delete(&models.LeaveRequest{}, id).Error
+func (r *LeaveRepository) Update(leave *models.LeaveRequest) error {
+	return r.db.Omit(clause.Associations).Save(leave).Error
 }
 
-func (r *LeaveRepository) CheckOverlapping(studentID uint, startDate, endDate time.Time, excludeID uint) (bool, error) {
-	var count int64
-	query := r.db.Model(&models.LeaveRequest{}).
-		Where("student_id = ?", studentID).
-		Where("status != ?", models.LeaveStatusRejected).
-		Where("(start_date <= ? AND end_date >= ?) OR (start_date <= ? AND end_date >= ?)",
-			endDate, startDate, startDate, endDate)
+// swaps the stored occurrences for the request's current ones after its dates or recurrence change
+func (r *LeaveRepository) ReplaceOccurrences(leave *models.LeaveRequest) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("leave_id = ?", leave.ID).Delete(&models.LeaveOccurrence{}).Error; err != nil {
+			return err
+		}
+		if len(leave.Occurrences) == 0 {
+			return nil
+		}
 
-	if excludeID > 0 {
-		query = query.Where("id != ?", excludeID)
-	}
+		for i := range leave.Occurrences {
+			leave.Occurrences[i].ID = 0
+			leave.Occurrences[i].LeaveID = leave.ID
+		}
+		return tx.Create(&leave.Occurrences).Error
+	})
+}
 
-	err := query.Count(&count).Error
-	return count > 0, err
+func (r *LeaveRepository) UpdateOccurrence(occurrence *models.LeaveOccurrence) error {
+	return r.db.Save(occurrence).Error
 }
 
-func (r *LeaveRepository) GetLeaveStats(startDate, endDate time.Time) (map[string]interface{}, error) {
-	var stats []struct {
-		LeaveType string
-		Count     int64
-	}
+// soft-deletes the request; its events stay behind
+func (r *LeaveRepository) Delete(id uint) error {
+	return r.db.Delete(&models.LeaveRequest{}, id).Error
+}
 
-	err := r.db.Model(&models.LeaveRequest{}).
-		Select("leave_type, COUNT(*) as count").
-		Where("created_at BETWEEN ? AND ?", startDate, endDate).
-		Group("leave_type").
-		Scan(&stats).Error
+// checks the student's other active requests for any shared dates, comparing periods when both cover part of a day
+func (r *LeaveRepository) CheckOverlapping(leave *models.LeaveRequest) (bool, error) {
+	var candidates []models.LeaveRequest
+	query := r.db.Where("student_id = ?", leave.StudentID).
+		Where("status NOT IN ?", models.InactiveLeaveStatuses).
+		Where("start_date <= ? AND end_date >= ?", leave.EndDate, leave.StartDate)
 
-	if err != nil {
-		return nil, err
+	if leave.ID > 0 {
+		query = query.Where("id != ?", leave.ID)
 	}
 
-	result := make(map[string]interface{})
-	for _, stat := range stats {
-		result[stat.LeaveType] = stat.Count
+	if err := query.Preload("Occurrences").Find(&candidates).Error; err != nil {
+		return false, err
 	}
 
-	return result, nil
+	for i := range candidates {
+		if leave.Overlaps(&candidates[i]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// counts the requests filed in the range under every catalogue entry, including those with none
+func (r *LeaveRepository) GetLeaveStats(startDate, endDate time.Time) ([]models.LeaveTypeStat, error) {
+	var stats []models.LeaveTypeStat
+	err := r.db.Table("leave_types AS t").
+		Select("t.code, t.name, t.active, COUNT(l.id) AS count").
+		Joins("LEFT JOIN leave_requests l ON l.leave_type = t.code AND l.created_at BETWEEN ? AND ? AND l.deleted_at IS NULL", startDate, endDate).
+		Group("t.code, t.name, t.active").
+		Order("t.name ASC").
+		Scan(&stats).Error
+	return stats, err
 }
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+)
+
+type LeaveTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveTypeRepository(db *gorm.DB) *LeaveTypeRepository {
+	return &LeaveTypeRepository{db: db}
+}
+
+// Active defaults to true on insert, so an inactive entry is retired straight after
+func (r *LeaveTypeRepository) Create(leaveType *models.LeaveTypeDefinition) error {
+	active := leaveType.Active
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(leaveType).Error; err != nil {
+			return err
+		}
+		if active {
+			return nil
+		}
+
+		leaveType.Active = false
+		return tx.Model(leaveType).Update("active", false).Error
+	})
+}
+
+func (r *LeaveTypeRepository) FindAll(activeOnly bool) ([]models.LeaveTypeDefinition, error) {
+	var leaveTypes []models.LeaveTypeDefinition
+	query := r.db.Order("name ASC")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+
+	err := query.Find(&leaveTypes).Error
+	return leaveTypes, err
+}
+
+// returns nil without error when the code is not in the catalogue
+func (r *LeaveTypeRepository) FindByCode(code models.LeaveType) (*models.LeaveTypeDefinition, error) {
+	var leaveType models.LeaveTypeDefinition
+	err := r.db.Where("code = ?", code).First(&leaveType).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &leaveType, nil
+}
+
+func (r *LeaveTypeRepository) Update(leaveType *models.LeaveTypeDefinition) error {
+	return r.db.Save(leaveType).Error
+}
+
+func (r *LeaveTypeRepository) Delete(id uint) error {
+	return r.db.Delete(&models.LeaveTypeDefinition{}, id).Error
+}
+
+// whether any leave request has been filed under the code
+func (r *LeaveTypeRepository) InUse(code models.LeaveType) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.LeaveRequest{}).Where("leave_type = ?", code).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) *LoginThrottleRepository {
+	return &LoginThrottleRepository{db: db}
+}
+
+// returns the throttles that exist among the keys
+func (r *LoginThrottleRepository) FindByKeys(keys []string) ([]models.LoginThrottle, error) {
+	var throttles []models.LoginThrottle
+	err := r.db.Where("key IN ?", keys).Find(&throttles).Error
+	return throttles, err
+}
+
+// counts a failure in one statement, starting over when the last one fell outside the window
+func (r *LoginThrottleRepository) RecordFailure(key string, now, windowStart time.Time) (*models.LoginThrottle, error) {
+	throttle := models.LoginThrottle{Key: key, Failures: 1, LastFailureAt: now}
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":        gorm.Expr("CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END", windowStart),
+			"last_failure_at": now,
+			"updated_at":      now,
+		}),
+	}).Create(&throttle).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Where("key = ?", key).First(&throttle).Error; err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *LoginThrottleRepository) Lock(key string, until time.Time) error {
+	return r.db.Model(&models.LoginThrottle{}).Where("key = ?", key).Update("locked_until", until).Error
+}
+
+func (r *LoginThrottleRepository) Delete(key string) error {
+	return r.db.Where("key = ?", key).Delete(&models.LoginThrottle{}).Error
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+)
+
+type MFARepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+// swaps the user's recovery codes for a new set
+func (r *MFARepository) ReplaceRecoveryCodes(userID uint, codes []models.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// marks an unused code with the hash used, failing when there is none
+func (r *MFARepository) UseRecoveryCode(userID uint, hash string, now time.Time) (bool, error) {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+// moves the last accepted TOTP step forward, failing when the step was already used
+func (r *MFARepository) AdvanceStep(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND mfa_last_step < ?", userID, step).
+		Update("mfa_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *MFARepository) SetSecret(userID uint, sealed string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).
+		Updates(map[string]interface{}{"mfa_secret": sealed, "mfa_enabled_at": nil, "mfa_last_step": 0}).Error
+}
+
+func (r *MFARepository) Enable(userID uint, now time.Time) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("mfa_enabled_at", now).Error
+}
+
+// drops the secret and recovery codes, leaving the account on password only
+func (r *MFARepository) Disable(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).
+			Updates(map[string]interface{}{"mfa_secret": "", "mfa_enabled_at": nil, "mfa_last_step": 0}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error
+	})
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+)
+
+type PolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewPolicyRepository(db *gorm.DB) *PolicyRepository {
+	return &PolicyRepository{db: db}
+}
+
+func (r *PolicyRepository) FindAll() ([]models.LeavePolicy, error) {
+	var policies []models.LeavePolicy
+	err := r.db.Preload("Blackouts").Order("leave_type ASC").Find(&policies).Error
+	return policies, err
+}
+
+// returns nil without error when the leave type has no policy
+func (r *PolicyRepository) FindByLeaveType(leaveType models.LeaveType) (*models.LeavePolicy, error) {
+	var policy models.LeavePolicy
+	err := r.db.Preload("Blackouts").Where("leave_type = ?", leaveType).First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// creates the policy or replaces the rules and blackouts of the existing one
+func (r *PolicyRepository) Save(policy *models.LeavePolicy) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.LeavePolicy
+		err := tx.Where("leave_type = ?", policy.LeaveType).First(&existing).Error
+		switch {
+		case err == nil:
+			policy.ID = existing.ID
+			policy.CreatedAt = existing.CreatedAt
+			if err := tx.Where("policy_id = ?", existing.ID).Delete(&models.PolicyBlackout{}).Error; err != nil {
+				return err
+			}
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		return tx.Save(policy).Error
+	})
+}
+
+func (r *PolicyRepository) Delete(leaveType models.LeaveType) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var policy models.LeavePolicy
+		if err := tx.Where("leave_type = ?", leaveType).First(&policy).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return models.ErrPolicyNotFound
+			}
+			return err
+		}
+
+		if err := tx.Where("policy_id = ?", policy.ID).Delete(&models.PolicyBlackout{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&policy).Error
+	})
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TokenRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRepository(db *gorm.DB) *TokenRepository {
+	return &TokenRepository{db: db}
+}
+
+func (r *TokenRepository) CreateRefresh(token *models.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *TokenRepository) FindRefreshByHash(hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// revokes a single refresh token, failing when it had already been used or revoked
+func (r *TokenRepository) ConsumeRefresh(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *TokenRepository) RevokeFamily(familyID string, now time.Time) error {
+	return r.db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error
+}
+
+func (r *TokenRepository) RevokeAllForUser(userID uint, now time.Time) error {
+	return r.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error
+}
+
+func (r *TokenRepository) Deny(token *models.RevokedToken) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+func (r *TokenRepository) IsDenied(jti string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *TokenRepository) CreateAccountToken(token *models.AccountToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *TokenRepository) FindAccountToken(hash string, purpose models.AccountTokenPurpose) (*models.AccountToken, error) {
+	var token models.AccountToken
+	err := r.db.Where("token_hash = ? AND purpose = ?", hash, purpose).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// marks the token used, failing when it was used concurrently or has expired
+func (r *TokenRepository) ConsumeAccountToken(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.AccountToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", id, now).
+		Update("used_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+// retires every outstanding token of the purpose, so only the newest link works
+func (r *TokenRepository) InvalidateAccountTokens(userID uint, purpose models.AccountTokenPurpose, now time.Time) error {
+	return r.db.Model(&models.AccountToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", now).Error
+}
+
+// drops denylist entries and refresh and account tokens that have expired on their own
+func (r *TokenRepository) PurgeExpired(now time.Time) error {
+	if err := r.db.Where("expires_at < ?", now).Delete(&models.RevokedToken{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("expires_at < ?", now).Delete(&models.RefreshToken{}).Error; err != nil {
+		return err
+	}
+	return r.db.Where("expires_at < ?", now).Delete(&models.AccountToken{}).Error
+}
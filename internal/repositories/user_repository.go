@@ -35,6 +35,12 @@ func (r *UserRepository) FindByID(id uint) (*models.User, error) {
 	return &user, nil
 }
 
+func (r *UserRepository) FindByRole(role models.Role) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("role = ?", role).Order("id ASC").Find(&users).Error
+	return users, err
+}
+
 func (r *UserRepository) FindAll(page, pageSize int) ([]models.User, int64, error) {
 	var users []models.User
 	var total int64
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+)
+
+// AccountService runs the emailed single-use flows: address verification and password reset
+type AccountService struct {
+	repo            *repositories.TokenRepository
+	userRepo        *repositories.UserRepository
+	tokenSvc        *TokenService
+	notificationSvc *NotificationService
+	cfg             core.AccountConfig
+	baseURL         string
+}
+
+func NewAccountService(
+	repo *repositories.TokenRepository,
+	userRepo *repositories.UserRepository,
+	tokenSvc *TokenService,
+	notificationSvc *NotificationService,
+	cfg core.AccountConfig,
+	baseURL string,
+) *AccountService {
+	return &AccountService{
+		repo:            repo,
+		userRepo:        userRepo,
+		tokenSvc:        tokenSvc,
+		notificationSvc: notificationSvc,
+		cfg:             cfg,
+		baseURL:         baseURL,
+	}
+}
+
+// refuses sign-in for accounts that still have to confirm their address
+func (s *AccountService) CheckVerified(user *models.User) error {
+	if s.cfg.RequireEmailVerification && !user.IsEmailVerified() {
+		return models.ErrEmailNotVerified
+	}
+	return nil
+}
+
+// emails a fresh verification link, retiring any sent earlier
+func (s *AccountService) SendVerification(user *models.User) error {
+	token, expiresAt, err := issueAccountToken(s.repo, user.ID, models.AccountTokenEmailVerification, s.cfg.VerificationTTL)
+	if err != nil {
+		return err
+	}
+	s.notificationSvc.SendEmailVerification(user, s.baseURL+"/verify-email?token="+url.QueryEscape(token), expiresAt)
+	return nil
+}
+
+// answers the same whether or not the address belongs to an unverified account
+func (s *AccountService) ResendVerification(email string) error {
+	user, _ := s.userRepo.FindByEmail(strings.TrimSpace(email))
+	if user == nil || user.IsEmailVerified() {
+		return nil
+	}
+	return s.SendVerification(user)
+}
+
+func (s *AccountService) VerifyEmail(token string) (*models.User, error) {
+	user, err := redeemAccountToken(s.repo, s.userRepo, token, models.AccountTokenEmailVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+		if err := s.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// emails a reset link; unknown addresses are ignored so the response reveals nothing
+func (s *AccountService) ForgotPassword(email string) error {
+	user, _ := s.userRepo.FindByEmail(strings.TrimSpace(email))
+	if user == nil {
+		return nil
+	}
+
+	token, expiresAt, err := issueAccountToken(s.repo, user.ID, models.AccountTokenPasswordReset, s.cfg.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+	s.notificationSvc.SendPasswordReset(user, s.baseURL+"/reset-password?token="+url.QueryEscape(token), expiresAt)
+	return nil
+}
+
+// sets the new password and signs the account out of every existing session
+func (s *AccountService) ResetPassword(token, password string) error {
+	user, err := redeemAccountToken(s.repo, s.userRepo, token, models.AccountTokenPasswordReset)
+	if err != nil {
+		return err
+	}
+
+	if err := user.HashPassword(password); err != nil {
+		return err
+	}
+	// the reset link reached the inbox, which proves the address too
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	if err := s.repo.InvalidateAccountTokens(user.ID, models.AccountTokenPasswordReset, time.Now()); err != nil {
+		return err
+	}
+	return s.tokenSvc.RevokeUser(user.ID)
+}
+
+// creates a single-use token, retiring earlier ones of the same purpose
+func issueAccountToken(
+	repo *repositories.TokenRepository,
+	userID uint,
+	purpose models.AccountTokenPurpose,
+	ttl time.Duration,
+) (string, time.Time, error) {
+	now := time.Now()
+	if err := repo.InvalidateAccountTokens(userID, purpose, now); err != nil {
+		return "", time.Time{}, err
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	stored := &models.AccountToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(token),
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := repo.CreateAccountToken(stored); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, stored.ExpiresAt, nil
+}
+
+// uses up the token and returns its account
+func redeemAccountToken(
+	repo *repositories.TokenRepository,
+	userRepo *repositories.UserRepository,
+	token string,
+	purpose models.AccountTokenPurpose,
+) (*models.User, error) {
+	stored, _ := repo.FindAccountToken(hashToken(token), purpose)
+	if stored == nil {
+		return nil, models.ErrInvalidAccountToken
+	}
+
+	consumed, err := repo.ConsumeAccountToken(stored.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, models.ErrInvalidAccountToken
+	}
+
+	user, _ := userRepo.FindByID(stored.UserID)
+	if user == nil {
+		return nil, models.ErrInvalidAccountToken
+	}
+	return user, nil
+}
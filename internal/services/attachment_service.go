@@ -0,0 +1,173 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+	"github.com/prannvs/campus-leave-system/internal/storage"
+)
+
+type AttachmentService struct {
+	repo          *repositories.AttachmentRepository
+	leaveRepo     *repositories.LeaveRepository
+	userRepo      *repositories.UserRepository
+	delegationSvc *DelegationService
+	policySvc     *PolicyService
+	store         storage.BlobStore
+	cfg           core.AttachmentConfig
+}
+
+func NewAttachmentService(
+	repo *repositories.AttachmentRepository,
+	leaveRepo *repositories.LeaveRepository,
+	userRepo *repositories.UserRepository,
+	delegationSvc *DelegationService,
+	policySvc *PolicyService,
+	store storage.BlobStore,
+	cfg core.AttachmentConfig,
+) *AttachmentService {
+	return &AttachmentService{
+		repo:          repo,
+		leaveRepo:     leaveRepo,
+		userRepo:      userRepo,
+		delegationSvc: delegationSvc,
+		policySvc:     policySvc,
+		store:         store,
+		cfg:           cfg,
+	}
+}
+
+// stores a document against one of the student's own leave requests
+func (s *AttachmentService) Upload(leaveID, studentID uint, fileName string, r io.Reader) (*models.LeaveAttachment, error) {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil || leave.StudentID != studentID {
+		return nil, models.ErrLeaveNotFound
+	}
+
+	// Read one byte past the limit so the count, not the size the client declared, catches an oversized file
+	counter := &countingReader{r: io.LimitReader(r, s.cfg.MaxSize+1)}
+
+	// Trust the file contents rather than the client's declared type
+	reader := bufio.NewReaderSize(counter, 512)
+	head, err := reader.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if !slices.Contains(s.cfg.AllowedTypes, contentType) {
+		return nil, models.ErrUnsupportedFileType
+	}
+
+	fileName = filepath.Base(fileName)
+	key := fmt.Sprintf("leaves/%d/%d%s", leave.ID, time.Now().UnixNano(), strings.ToLower(filepath.Ext(fileName)))
+	if err := s.store.Put(key, reader); err != nil {
+		return nil, err
+	}
+	if counter.n > s.cfg.MaxSize {
+		s.store.Delete(key)
+		return nil, models.ErrAttachmentTooLarge
+	}
+
+	attachment := &models.LeaveAttachment{
+		LeaveID:     leave.ID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        counter.n,
+		StorageKey:  key,
+		UploadedBy:  studentID,
+	}
+	if err := s.repo.Create(attachment); err != nil {
+		s.store.Delete(key)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (s *AttachmentService) List(leaveID, viewerID uint) ([]models.LeaveAttachment, error) {
+	if _, err := s.visibleLeave(leaveID, viewerID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByLeaveID(leaveID)
+}
+
+// opens the document for the student or an approver who can see the leave
+func (s *AttachmentService) Open(leaveID, attachmentID, viewerID uint) (*models.LeaveAttachment, io.ReadCloser, error) {
+	if _, err := s.visibleLeave(leaveID, viewerID); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.repo.FindByID(attachmentID)
+	if err != nil || attachment.LeaveID != leaveID {
+		return nil, nil, models.ErrAttachmentNotFound
+	}
+
+	content, err := s.store.Get(attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// fails when the leave needs a document, for its length or because it was filed after the fact, and none is attached
+func (s *AttachmentService) CheckRequired(leave *models.LeaveRequest) error {
+	policy, err := s.policySvc.For(leave.LeaveType)
+	if err != nil {
+		return err
+	}
+	if !leave.Retroactive && !policy.RequiresAttachment(leave.Days) {
+		return nil
+	}
+
+	count, err := s.repo.CountByLeaveID(leave.ID)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return models.ErrAttachmentRequired
+	}
+	return nil
+}
+
+func (s *AttachmentService) visibleLeave(leaveID, viewerID uint) (*models.LeaveRequest, error) {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil {
+		return nil, models.ErrLeaveNotFound
+	}
+
+	viewer, err := s.userRepo.FindByID(viewerID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	delegators, err := s.delegationSvc.ActiveDelegators(viewerID, models.DelegationScopeLeaveApproval)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canViewLeave(leave, viewer, delegators) {
+		return nil, models.ErrLeaveNotFound
+	}
+	return leave, nil
+}
+
+// tallies the bytes actually read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -3,21 +3,37 @@ package services
 import (
 	"time"
 
+	"github.com/prannvs/campus-leave-system/internal/core"
 	"github.com/prannvs/campus-leave-system/internal/models"
 	"github.com/prannvs/campus-leave-system/internal/repositories"
 )
 
 type AttendanceService struct {
-	repo *repositories.AttendanceRepository
+	repo        *repositories.AttendanceRepository
+	calendarSvc *CalendarService
+	timetable   core.TimetableConfig
 }
 
-func NewAttendanceService(repo *repositories.AttendanceRepository) *AttendanceService {
-	return &AttendanceService{repo: repo}
+func NewAttendanceService(
+	repo *repositories.AttendanceRepository,
+	calendarSvc *CalendarService,
+	timetable core.TimetableConfig,
+) *AttendanceService {
+	return &AttendanceService{
+		repo:        repo,
+		calendarSvc: calendarSvc,
+		timetable:   timetable,
+	}
 }
 
-func (s *AttendanceService) MarkAttendance(studentID uint, date time.Time, present bool, markedBy uint) error {
+// period zero marks the whole day
+func (s *AttendanceService) MarkAttendance(studentID uint, date time.Time, period int, present bool, markedBy uint) error {
+	if period > s.timetable.PeriodsPerDay {
+		return models.ErrInvalidPeriodRange
+	}
+
 	// Check if attendance already exists
-	existing, _ := s.repo.FindByStudentAndDate(studentID, date)
+	existing, _ := s.repo.FindByStudentAndDate(studentID, date, period)
 	if existing != nil {
 		return models.ErrAttendanceExists
 	}
@@ -25,19 +41,30 @@ func (s *AttendanceService) MarkAttendance(studentID uint, date time.Time, prese
 	attendance := &models.Attendance{
 		StudentID: studentID,
 		Date:      date,
+		Period:    period,
 		Present:   present,
-		MarkedBy:  markedBy,
+		MarkedBy:  &markedBy,
 	}
 
 	return s.repo.Create(attendance)
 }
 
+// holidays and weekly offs are left out of the denominator
 func (s *AttendanceService) GetStats(studentID uint, startDate, endDate time.Time) (*models.AttendanceStats, error) {
-	return s.repo.GetStats(studentID, startDate, endDate)
+	excluded, err := s.calendarSvc.NonWorkingDates(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetStats(studentID, startDate, endDate, excluded, s.timetable.PeriodsPerDay)
 }
 
 func (s *AttendanceService) GetLowAttendanceStudents(threshold float64) ([]map[string]interface{}, error) {
 	now := time.Now()
 	startDate := now.AddDate(0, -1, 0) // Last month
-	return s.repo.GetLowAttendanceStudents(threshold, startDate, now)
+
+	excluded, err := s.calendarSvc.NonWorkingDates(startDate, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetLowAttendanceStudents(threshold, startDate, now, excluded, s.timetable.PeriodsPerDay)
 }
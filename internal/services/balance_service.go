@@ -0,0 +1,325 @@
+package services
+
+import (
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type BalanceService struct {
+	repo          *repositories.BalanceRepository
+	userRepo      *repositories.UserRepository
+	leaveTypeRepo *repositories.LeaveTypeRepository
+	calendarSvc   *CalendarService
+}
+
+func NewBalanceService(
+	repo *repositories.BalanceRepository,
+	userRepo *repositories.UserRepository,
+	leaveTypeRepo *repositories.LeaveTypeRepository,
+	calendarSvc *CalendarService,
+) *BalanceService {
+	return &BalanceService{
+		repo:          repo,
+		userRepo:      userRepo,
+		leaveTypeRepo: leaveTypeRepo,
+		calendarSvc:   calendarSvc,
+	}
+}
+
+func (s *BalanceService) WithTx(tx *gorm.DB) *BalanceService {
+	txSvc := *s
+	txSvc.repo = s.repo.WithTx(tx)
+	return &txSvc
+}
+
+func (s *BalanceService) GetBalances(studentID uint) ([]models.LeaveBalance, error) {
+	student, err := s.userRepo.FindByID(studentID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	leaveTypes, err := s.leaveTypeRepo.FindAll(true)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]models.LeaveBalance, 0, len(leaveTypes))
+	for _, leaveType := range leaveTypes {
+		balance, err := s.balanceFor(student, leaveType.Code, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		balances = append(balances, *balance)
+	}
+	return balances, nil
+}
+
+// serialises quota checks for the student within the current transaction
+func (s *BalanceService) LockStudent(studentID uint) error {
+	return s.repo.LockStudent(studentID)
+}
+
+// rejects the leave when it would exceed the remaining quota of any period it falls in;
+// its days count against the period of the dates they fall on
+func (s *BalanceService) CheckAvailable(leave *models.LeaveRequest) error {
+	quota, err := s.quotaFor(leave)
+	if err != nil || quota == nil {
+		return err
+	}
+
+	shares, err := s.shares(quota, leave.ActiveOccurrences())
+	if err != nil {
+		return err
+	}
+
+	var periods []time.Time
+	requested := make(map[time.Time]float64)
+	for _, share := range shares {
+		periodStart, _ := quota.Period.Bounds(share.date)
+		if _, ok := requested[periodStart]; !ok {
+			periods = append(periods, periodStart)
+		}
+		requested[periodStart] += share.days
+	}
+
+	for _, periodStart := range periods {
+		balance, err := s.periodBalance(leave.StudentID, leave.LeaveType, quota, periodStart)
+		if err != nil {
+			return err
+		}
+		if requested[periodStart] > balance.Remaining {
+			return &models.InsufficientBalanceError{
+				LeaveType: leave.LeaveType,
+				Requested: requested[periodStart],
+				Remaining: balance.Remaining,
+			}
+		}
+	}
+	return nil
+}
+
+// debits the leave's days in the quota periods they fall in, so a series or a span across a
+// term boundary spends each period's own quota
+func (s *BalanceService) Debit(leave *models.LeaveRequest) error {
+	return s.post(leave, models.LedgerEntryDebit, leave.ActiveOccurrences())
+}
+
+// debits an approved extension against its parent so cancelling the parent returns both
+func (s *BalanceService) DebitExtension(parent, extension *models.LeaveRequest) error {
+	return s.post(parent, models.LedgerEntryDebit, extension.ActiveOccurrences())
+}
+
+// reverses whatever is still debited against the leave, on the dates it was debited
+func (s *BalanceService) Credit(leave *models.LeaveRequest) error {
+	debited, err := s.repo.NetByDate(leave.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, debit := range debited {
+		if err := s.entry(leave, models.LedgerEntryCredit, debit.Days, debit.Date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// returns what was debited for occurrences of a series as they are cancelled
+func (s *BalanceService) CreditOccurrences(leave *models.LeaveRequest, occurrences []*models.LeaveOccurrence) error {
+	spans := make([]models.LeaveOccurrence, len(occurrences))
+	for i, occurrence := range occurrences {
+		spans[i] = *occurrence
+	}
+	return s.post(leave, models.LedgerEntryCredit, spans)
+}
+
+// a share of a leave's days that falls in one quota period, dated at its first day there
+type periodShare struct {
+	date time.Time
+	days float64
+}
+
+// splits the spans by the quota periods they fall in, cutting a span that crosses a boundary
+// at its working dates; with no quota every span is a single share
+func (s *BalanceService) shares(quota *models.LeaveQuota, spans []models.LeaveOccurrence) ([]periodShare, error) {
+	var shares []periodShare
+	for _, span := range spans {
+		if quota != nil {
+			_, periodEnd := quota.Period.Bounds(span.StartDate)
+			if models.DateOnly(span.EndDate).After(periodEnd) {
+				split, err := s.split(quota, span)
+				if err != nil {
+					return nil, err
+				}
+				shares = append(shares, split...)
+				continue
+			}
+		}
+		shares = append(shares, periodShare{date: span.StartDate, days: span.Days})
+	}
+	return shares, nil
+}
+
+// partial-day leave never spans two days, so a span that is cut counts whole working days
+func (s *BalanceService) split(quota *models.LeaveQuota, span models.LeaveOccurrence) ([]periodShare, error) {
+	dates, err := s.calendarSvc.WorkingDates(span.StartDate, span.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var shares []periodShare
+	var periodEnd time.Time
+	for _, date := range dates {
+		if len(shares) > 0 && !date.After(periodEnd) {
+			shares[len(shares)-1].days++
+			continue
+		}
+		_, periodEnd = quota.Period.Bounds(date)
+		shares = append(shares, periodShare{date: date, days: 1})
+	}
+	return shares, nil
+}
+
+// writes one ledger entry per period share of the spans, all against the leave
+func (s *BalanceService) post(leave *models.LeaveRequest, entryType models.LedgerEntryType, spans []models.LeaveOccurrence) error {
+	quota, err := s.quotaFor(leave)
+	if err != nil {
+		return err
+	}
+
+	shares, err := s.shares(quota, spans)
+	if err != nil {
+		return err
+	}
+	for _, share := range shares {
+		if err := s.entry(leave, entryType, share.days, share.date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BalanceService) entry(leave *models.LeaveRequest, entryType models.LedgerEntryType, days float64, date time.Time) error {
+	if days <= 0 {
+		return nil
+	}
+
+	return s.repo.CreateEntry(&models.LeaveLedgerEntry{
+		StudentID: leave.StudentID,
+		LeaveID:   leave.ID,
+		LeaveType: leave.LeaveType,
+		EntryType: entryType,
+		Days:      days,
+		Date:      date,
+	})
+}
+
+// the quota that applies to the leave's student and type, nil when the type is unlimited
+func (s *BalanceService) quotaFor(leave *models.LeaveRequest) (*models.LeaveQuota, error) {
+	student, err := s.userRepo.FindByID(leave.StudentID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+	return s.findQuota(student, leave.LeaveType)
+}
+
+func (s *BalanceService) GetQuotas() ([]models.LeaveQuota, error) {
+	return s.repo.FindQuotas()
+}
+
+func (s *BalanceService) CreateQuota(req models.CreateQuotaRequest) (*models.LeaveQuota, error) {
+	definition, err := s.leaveTypeRepo.FindByCode(models.LeaveType(req.LeaveType))
+	if err != nil {
+		return nil, err
+	}
+	if definition == nil {
+		return nil, models.ErrLeaveTypeNotFound
+	}
+
+	quota := &models.LeaveQuota{
+		LeaveType: models.LeaveType(req.LeaveType),
+		Period:    models.QuotaPeriod(req.Period),
+		Days:      req.Days,
+		Dept:      req.Dept,
+		Year:      req.Year,
+	}
+
+	if err := s.repo.CreateQuota(quota); err != nil {
+		return nil, err
+	}
+	return quota, nil
+}
+
+func (s *BalanceService) DeleteQuota(id uint) error {
+	return s.repo.DeleteQuota(id)
+}
+
+func (s *BalanceService) balanceFor(student *models.User, leaveType models.LeaveType, date time.Time) (*models.LeaveBalance, error) {
+	quota, err := s.findQuota(student, leaveType)
+	if err != nil {
+		return nil, err
+	}
+	if quota == nil {
+		return &models.LeaveBalance{LeaveType: leaveType, Unlimited: true}, nil
+	}
+	return s.periodBalance(student.ID, leaveType, quota, date)
+}
+
+// what is left of the quota in the period containing the date
+func (s *BalanceService) periodBalance(studentID uint, leaveType models.LeaveType, quota *models.LeaveQuota, date time.Time) (*models.LeaveBalance, error) {
+	periodStart, periodEnd := quota.Period.Bounds(date)
+	used, err := s.repo.SumUsed(studentID, leaveType, periodStart, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := quota.Days - used
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &models.LeaveBalance{
+		LeaveType:   leaveType,
+		Period:      quota.Period,
+		PeriodStart: &periodStart,
+		PeriodEnd:   &periodEnd,
+		Quota:       quota.Days,
+		Used:        used,
+		Remaining:   remaining,
+	}, nil
+}
+
+// picks the most specific quota matching the student, falling back to the catalogue default,
+// nil when the type is unlimited
+func (s *BalanceService) findQuota(student *models.User, leaveType models.LeaveType) (*models.LeaveQuota, error) {
+	quotas, err := s.repo.FindQuotasByType(leaveType)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *models.LeaveQuota
+	for i := range quotas {
+		if !quotas[i].Matches(student) {
+			continue
+		}
+		if best == nil || quotas[i].Specificity() > best.Specificity() {
+			best = &quotas[i]
+		}
+	}
+	if best != nil {
+		return best, nil
+	}
+
+	definition, err := s.leaveTypeRepo.FindByCode(leaveType)
+	if err != nil || definition == nil || definition.DefaultQuota == nil {
+		return nil, err
+	}
+	return &models.LeaveQuota{
+		LeaveType: leaveType,
+		Period:    definition.DefaultQuotaPeriod,
+		Days:      *definition.DefaultQuota,
+	}, nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+)
+
+type CalendarService struct {
+	repo *repositories.CalendarRepository
+}
+
+func NewCalendarService(repo *repositories.CalendarRepository) *CalendarService {
+	return &CalendarService{repo: repo}
+}
+
+// loads the calendar entries relevant to the given range
+func (s *CalendarService) Load(startDate, endDate time.Time) (*models.Calendar, error) {
+	terms, err := s.repo.FindTerms()
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.FindEventsBetween(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	weeklyOff, err := s.weeklyOff()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Calendar{
+		Terms:     terms,
+		Events:    events,
+		WeeklyOff: weeklyOff,
+	}, nil
+}
+
+func (s *CalendarService) WorkingDays(startDate, endDate time.Time) (int, error) {
+	dates, err := s.WorkingDates(startDate, endDate)
+	return len(dates), err
+}
+
+func (s *CalendarService) WorkingDates(startDate, endDate time.Time) ([]time.Time, error) {
+	calendar, err := s.Load(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	return calendar.WorkingDates(startDate, endDate), nil
+}
+
+func (s *CalendarService) NonWorkingDates(startDate, endDate time.Time) ([]time.Time, error) {
+	calendar, err := s.Load(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	return calendar.NonWorkingDates(startDate, endDate), nil
+}
+
+// returns every configured term and event
+func (s *CalendarService) GetCalendar() (*models.Calendar, error) {
+	terms, err := s.repo.FindTerms()
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.FindEvents()
+	if err != nil {
+		return nil, err
+	}
+
+	weeklyOff, err := s.weeklyOff()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Calendar{
+		Terms:     terms,
+		Events:    events,
+		WeeklyOff: weeklyOff,
+	}, nil
+}
+
+func (s *CalendarService) CreateTerm(req models.CreateTermRequest) (*models.AcademicTerm, error) {
+	startDate, endDate, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	term := &models.AcademicTerm{
+		Name:      req.Name,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+	if err := s.repo.CreateTerm(term); err != nil {
+		return nil, err
+	}
+	return term, nil
+}
+
+func (s *CalendarService) DeleteTerm(id uint) error {
+	return s.repo.DeleteTerm(id)
+}
+
+func (s *CalendarService) CreateEvent(req models.CreateCalendarEventRequest) (*models.CalendarEvent, error) {
+	startDate, endDate, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &models.CalendarEvent{
+		Name:      req.Name,
+		Kind:      models.CalendarEventKind(req.Kind),
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+	if err := s.repo.CreateEvent(event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (s *CalendarService) DeleteEvent(id uint) error {
+	return s.repo.DeleteEvent(id)
+}
+
+// replaces the weekly offs, an empty list restores the default
+func (s *CalendarService) SetWeeklyOff(req models.SetWeeklyOffRequest) ([]time.Weekday, error) {
+	offs := make([]models.WeeklyOff, 0, len(req.Weekdays))
+	for _, name := range req.Weekdays {
+		weekday, ok := models.ParseWeekday(name)
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday: %s", name)
+		}
+		offs = append(offs, models.WeeklyOff{Weekday: weekday})
+	}
+
+	if err := s.repo.ReplaceWeeklyOffs(offs); err != nil {
+		return nil, err
+	}
+	return s.weeklyOff()
+}
+
+func (s *CalendarService) weeklyOff() ([]time.Weekday, error) {
+	offs, err := s.repo.FindWeeklyOffs()
+	if err != nil {
+		return nil, err
+	}
+	if len(offs) == 0 {
+		return models.DefaultWeeklyOff, nil
+	}
+
+	weekdays := make([]time.Weekday, 0, len(offs))
+	for _, off := range offs {
+		weekdays = append(weekdays, off.Weekday)
+	}
+	return weekdays, nil
+}
+
+func parseDateRange(start, end string) (time.Time, time.Time, error) {
+	startDate, err := time.Parse("2006-01-02", start)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	endDate, err := time.Parse("2006-01-02", end)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if endDate.Before(startDate) {
+		return time.Time{}, time.Time{}, models.ErrInvalidDateRange
+	}
+	return startDate, endDate, nil
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"log"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+)
+
+// CommentService runs the conversation between a student and the approvers of their request
+type CommentService struct {
+	repo            *repositories.CommentRepository
+	leaveSvc        *LeaveService
+	notificationSvc *NotificationService
+}
+
+func NewCommentService(
+	repo *repositories.CommentRepository,
+	leaveSvc *LeaveService,
+	notificationSvc *NotificationService,
+) *CommentService {
+	return &CommentService{
+		repo:            repo,
+		leaveSvc:        leaveSvc,
+		notificationSvc: notificationSvc,
+	}
+}
+
+func (s *CommentService) List(leaveID, viewerID uint) ([]models.LeaveComment, error) {
+	if _, _, err := s.leaveSvc.ViewLeave(leaveID, viewerID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByLeaveID(leaveID)
+}
+
+// posts a comment on an open request; an approver may ask for more information, which
+// holds the request with the student until they reply
+func (s *CommentService) Add(leaveID, authorID uint, req models.AddCommentRequest) (*models.LeaveComment, error) {
+	leave, author, err := s.leaveSvc.ViewLeave(leaveID, authorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !leave.Status.IsOpen() {
+		return nil, models.ErrLeaveNotPending
+	}
+
+	isStudent := author.ID == leave.StudentID
+	switch {
+	case req.RequestInfo && isStudent:
+		return nil, models.ErrNotAwaitingApprover
+	case req.RequestInfo:
+		if err := s.leaveSvc.RequestInfo(leave, author); err != nil {
+			return nil, err
+		}
+	case isStudent:
+		if err := s.leaveSvc.ResumeReview(leave); err != nil {
+			return nil, err
+		}
+	}
+
+	comment := &models.LeaveComment{
+		LeaveID:       leave.ID,
+		AuthorID:      author.ID,
+		Body:          req.Body,
+		RequestedInfo: req.RequestInfo,
+	}
+	if err := s.repo.Create(comment); err != nil {
+		return nil, err
+	}
+
+	recipients, err := s.recipients(leave, author)
+	if err != nil {
+		log.Printf("Failed to resolve comment recipients for leave %d: %v", leave.ID, err)
+	}
+	s.notificationSvc.SendCommentNotification(leave, comment, recipients)
+
+	return comment, nil
+}
+
+// everyone on the thread other than the author: the student, earlier commenters and,
+// for the student's own comments, the approvers the request is waiting on
+func (s *CommentService) recipients(leave *models.LeaveRequest, author *models.User) ([]models.User, error) {
+	users := []models.User{leave.Student}
+
+	comments, err := s.repo.FindByLeaveID(leave.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		users = append(users, comment.Author)
+	}
+
+	if author.ID == leave.StudentID {
+		approvers, err := s.leaveSvc.AwaitingApprovers(leave)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, approvers...)
+	}
+
+	seen := map[uint]bool{author.ID: true}
+	var recipients []models.User
+	for _, user := range users {
+		if seen[user.ID] {
+			continue
+		}
+		seen[user.ID] = true
+		recipients = append(recipients, user)
+	}
+	return recipients, nil
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/auth"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+)
+
+// ConsentService asks guardians to approve the leave of minors and hostel residents
+type ConsentService struct {
+	repo            *repositories.ConsentRepository
+	leaveRepo       *repositories.LeaveRepository
+	notificationSvc *NotificationService
+	signer          *auth.Signer
+	cfg             core.ConsentConfig
+	baseURL         string
+}
+
+func NewConsentService(
+	repo *repositories.ConsentRepository,
+	leaveRepo *repositories.LeaveRepository,
+	notificationSvc *NotificationService,
+	signer *auth.Signer,
+	cfg core.ConsentConfig,
+	baseURL string,
+) *ConsentService {
+	return &ConsentService{
+		repo:            repo,
+		leaveRepo:       leaveRepo,
+		notificationSvc: notificationSvc,
+		signer:          signer,
+		cfg:             cfg,
+		baseURL:         baseURL,
+	}
+}
+
+// whether the leave needs the guardian's word; retroactive leave and extensions of consented leave do not
+func (s *ConsentService) Required(leave *models.LeaveRequest, student *models.User) bool {
+	if leave.Retroactive || leave.IsExtension() || !slices.Contains(s.cfg.LeaveTypes, string(leave.LeaveType)) {
+		return false
+	}
+	return student.Hostel != "" || student.IsMinorOn(leave.StartDate)
+}
+
+// fails when the leave will need consent but there is no guardian to ask
+func (s *ConsentService) CheckGuardian(leave *models.LeaveRequest, student *models.User) error {
+	if s.Required(leave, student) && student.GuardianEmail == "" {
+		return models.ErrGuardianRequired
+	}
+	return nil
+}
+
+// emails the guardian a fresh link, starting over any earlier answer since the request may have changed
+func (s *ConsentService) Request(leave *models.LeaveRequest, student *models.User) error {
+	if !s.Required(leave, student) {
+		return nil
+	}
+
+	consent, err := s.repo.FindByLeaveID(leave.ID)
+	if err != nil {
+		return err
+	}
+	if consent == nil {
+		consent = &models.GuardianConsent{LeaveID: leave.ID}
+	}
+
+	consent.GuardianName = student.GuardianName
+	consent.GuardianEmail = student.GuardianEmail
+	consent.Status = models.ConsentPending
+	consent.RespondedAt = nil
+	consent.Remarks = nil
+	return s.send(consent, leave, student)
+}
+
+// sends the guardian a new link for a request still waiting on them
+func (s *ConsentService) Resend(leaveID, studentID uint) error {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil || leave.StudentID != studentID {
+		return models.ErrLeaveNotFound
+	}
+	if !leave.Status.IsOpen() {
+		return models.ErrLeaveNotPending
+	}
+
+	consent, err := s.repo.FindByLeaveID(leave.ID)
+	if err != nil {
+		return err
+	}
+	if consent == nil {
+		return models.ErrConsentNotFound
+	}
+	if consent.Status != models.ConsentPending {
+		return models.ErrInvalidConsentLink
+	}
+
+	return s.send(consent, leave, &leave.Student)
+}
+
+// fails unless the guardian has consented, for leave that needs it
+func (s *ConsentService) Check(leave *models.LeaveRequest) error {
+	if !s.Required(leave, &leave.Student) {
+		return nil
+	}
+
+	consent, err := s.repo.FindByLeaveID(leave.ID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case consent == nil, consent.Status == models.ConsentPending:
+		return models.ErrConsentPending
+	case consent.Status == models.ConsentDeclined:
+		return models.ErrConsentDeclined
+	default:
+		return nil
+	}
+}
+
+func (s *ConsentService) GetForLeave(leaveID uint) (*models.GuardianConsent, error) {
+	consent, err := s.repo.FindByLeaveID(leaveID)
+	if err != nil {
+		return nil, err
+	}
+	if consent == nil {
+		return nil, models.ErrConsentNotFound
+	}
+	return consent, nil
+}
+
+// the consent and leave behind a link, for the guardian to review before answering
+func (s *ConsentService) View(token string) (*models.GuardianConsent, error) {
+	return s.fromToken(token)
+}
+
+// records the guardian's answer and uses up the link
+func (s *ConsentService) Respond(token string, req models.ConsentDecisionRequest) (*models.GuardianConsent, error) {
+	consent, err := s.fromToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := consent.Nonce
+	now := time.Now()
+	consent.Status = models.ConsentStatus(req.Decision)
+	consent.Remarks = req.Remarks
+	consent.RespondedAt = &now
+	consent.Nonce = ""
+	responded, err := s.repo.Respond(consent, nonce)
+	if err != nil {
+		return nil, err
+	}
+	if !responded {
+		return nil, models.ErrInvalidConsentLink
+	}
+
+	s.notificationSvc.SendConsentResponseNotification(consent.Leave, consent)
+	return consent, nil
+}
+
+func (s *ConsentService) send(consent *models.GuardianConsent, leave *models.LeaveRequest, student *models.User) error {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	consent.Nonce = hex.EncodeToString(nonce)
+	consent.ExpiresAt = time.Now().Add(s.cfg.LinkTTL)
+
+	var err error
+	if consent.ID == 0 {
+		err = s.repo.Create(consent)
+	} else {
+		err = s.repo.Update(consent)
+	}
+	if err != nil {
+		return err
+	}
+
+	token := s.signer.Sign(fmt.Sprintf("%d:%s:%d", consent.ID, consent.Nonce, consent.ExpiresAt.Unix()))
+	link := s.baseURL + "/api/consent/" + token
+	s.notificationSvc.SendConsentRequest(consent, leave, student, link)
+	return nil
+}
+
+// resolves a link to a consent still waiting on the guardian
+func (s *ConsentService) fromToken(token string) (*models.GuardianConsent, error) {
+	payload, err := s.signer.Verify(token)
+	if err != nil {
+		return nil, models.ErrInvalidConsentLink
+	}
+
+	parts := strings.Split(payload, ":")
+	if len(parts) != 3 {
+		return nil, models.ErrInvalidConsentLink
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, models.ErrInvalidConsentLink
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || time.Now().After(time.Unix(expires, 0)) {
+		return nil, models.ErrInvalidConsentLink
+	}
+
+	consent, err := s.repo.FindByID(uint(id))
+	if err != nil || consent.Nonce == "" || consent.Nonce != parts[1] || consent.Status != models.ConsentPending {
+		return nil, models.ErrInvalidConsentLink
+	}
+	if consent.Leave == nil || !consent.Leave.Status.IsOpen() {
+		return nil, models.ErrInvalidConsentLink
+	}
+	return consent, nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+)
+
+type DelegationService struct {
+	repo     *repositories.DelegationRepository
+	userRepo *repositories.UserRepository
+}
+
+func NewDelegationService(repo *repositories.DelegationRepository, userRepo *repositories.UserRepository) *DelegationService {
+	return &DelegationService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// staff delegate their own duties, admins may set one up for anyone
+func (s *DelegationService) Create(creatorID uint, req models.CreateDelegationRequest) (*models.Delegation, error) {
+	creator, err := s.userRepo.FindByID(creatorID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	delegatorID := creatorID
+	if req.DelegatorID != 0 && req.DelegatorID != creatorID {
+		if creator.Role != models.RoleAdmin {
+			return nil, models.ErrInvalidRole
+		}
+		delegatorID = req.DelegatorID
+	}
+
+	delegator, err := s.userRepo.FindByID(delegatorID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	delegate, err := s.userRepo.FindByID(req.DelegateID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	if delegator.ID == delegate.ID {
+		return nil, models.ErrInvalidDelegation
+	}
+	if !isApproverRole(delegator.Role) || !isApproverRole(delegate.Role) {
+		return nil, models.ErrInvalidDelegation
+	}
+
+	startDate, endDate, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := models.DelegationScope(req.Scope)
+	if scope == "" {
+		scope = models.DelegationScopeAll
+	}
+
+	delegation := &models.Delegation{
+		DelegatorID: delegator.ID,
+		DelegateID:  delegate.ID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Scope:       scope,
+		Reason:      req.Reason,
+	}
+	if err := s.repo.Create(delegation); err != nil {
+		return nil, err
+	}
+
+	delegation.Delegator = delegator
+	delegation.Delegate = delegate
+	return delegation, nil
+}
+
+func (s *DelegationService) List(userID uint) ([]models.Delegation, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	if user.Role == models.RoleAdmin {
+		return s.repo.FindForUser(0)
+	}
+	return s.repo.FindForUser(userID)
+}
+
+func (s *DelegationService) Revoke(id, userID uint) error {
+	delegation, err := s.repo.FindByID(id)
+	if err != nil {
+		return models.ErrDelegationNotFound
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return models.ErrUserNotFound
+	}
+
+	if delegation.DelegatorID != userID && user.Role != models.RoleAdmin {
+		return models.ErrDelegationNotFound
+	}
+
+	now := time.Now()
+	delegation.RevokedAt = &now
+	return s.repo.Update(delegation)
+}
+
+// users the delegate may currently act for within the given scope
+func (s *DelegationService) ActiveDelegators(delegateID uint, scope models.DelegationScope) ([]models.User, error) {
+	delegations, err := s.repo.FindActiveForDelegate(delegateID, models.DateOnly(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	var delegators []models.User
+	for _, delegation := range delegations {
+		if delegation.Delegator != nil && delegation.Covers(scope) {
+			delegators = append(delegators, *delegation.Delegator)
+		}
+	}
+	return delegators, nil
+}
+
+// roles the user stands in for when approving leave, used for access to the approval routes only
+func (s *DelegationService) DelegatedRoles(userID uint) ([]models.Role, error) {
+	delegators, err := s.ActiveDelegators(userID, models.DelegationScopeLeaveApproval)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]models.Role, 0, len(delegators))
+	for _, delegator := range delegators {
+		roles = append(roles, delegator.Role)
+	}
+	return roles, nil
+}
+
+func isApproverRole(role models.Role) bool {
+	return role == models.RoleFaculty || role == models.RoleWarden || role == models.RoleAdmin
+}
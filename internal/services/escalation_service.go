@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+)
+
+// EscalationService chases requests an approver has left sitting past their SLA
+type EscalationService struct {
+	leaveSvc        *LeaveService
+	userRepo        *repositories.UserRepository
+	notificationSvc *NotificationService
+	cfg             core.SLAConfig
+}
+
+func NewEscalationService(
+	leaveSvc *LeaveService,
+	userRepo *repositories.UserRepository,
+	notificationSvc *NotificationService,
+	cfg core.SLAConfig,
+) *EscalationService {
+	return &EscalationService{
+		leaveSvc:        leaveSvc,
+		userRepo:        userRepo,
+		notificationSvc: notificationSvc,
+		cfg:             cfg,
+	}
+}
+
+// runs the escalation check on every tick until the context is cancelled
+func (s *EscalationService) Start(ctx context.Context) {
+	interval := s.cfg.CheckInterval
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.RunOnce(); err != nil {
+					log.Printf("Leave escalation run failed: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// flags overdue requests and alerts the next level of their chain, or the admins when there is
+// none, copying the approvers the request is waiting on
+func (s *EscalationService) RunOnce() error {
+	leaves, err := s.leaveSvc.GetOverdue()
+	if err != nil {
+		return err
+	}
+	if len(leaves) == 0 {
+		return nil
+	}
+
+	admins, err := s.userRepo.FindByRole(models.RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	for i := range leaves {
+		leave, err := s.leaveSvc.MarkEscalated(leaves[i].ID)
+		if err != nil {
+			log.Printf("Failed to escalate leave %d: %v", leaves[i].ID, err)
+			continue
+		}
+		// decided or escalated since the overdue list was read
+		if leave == nil {
+			continue
+		}
+
+		// A request that cannot be approved automatically stays open for its approvers
+		if leave.LeaveType == models.LeaveTypeEmergency && s.cfg.AutoApproveEmergency {
+			err := s.leaveSvc.AutoApprove(leave.ID)
+			if err == nil {
+				continue
+			}
+			log.Printf("Leave %d not auto-approved: %v", leave.ID, err)
+		}
+
+		recipients, err := s.leaveSvc.NextApprovers(leave)
+		if err != nil {
+			log.Printf("Failed to find the next approvers for leave %d: %v", leave.ID, err)
+			continue
+		}
+		if len(recipients) == 0 {
+			recipients = admins
+		}
+
+		current, err := s.leaveSvc.AwaitingApprovers(leave)
+		if err != nil {
+			log.Printf("Failed to find approvers for leave %d: %v", leave.ID, err)
+		}
+		for _, approver := range current {
+			if !slices.ContainsFunc(recipients, func(user models.User) bool { return user.ID == approver.ID }) {
+				recipients = append(recipients, approver)
+			}
+		}
+		s.notificationSvc.SendEscalationNotification(leave, recipients)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/auth"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type GatePassService struct {
+	repo     *repositories.GatePassRepository
+	userRepo *repositories.UserRepository
+	signer   *auth.Signer
+}
+
+func NewGatePassService(
+	repo *repositories.GatePassRepository,
+	userRepo *repositories.UserRepository,
+	signer *auth.Signer,
+) *GatePassService {
+	return &GatePassService{
+		repo:     repo,
+		userRepo: userRepo,
+		signer:   signer,
+	}
+}
+
+func (s *GatePassService) WithTx(tx *gorm.DB) *GatePassService {
+	txSvc := *s
+	txSvc.repo = s.repo.WithTx(tx)
+	return &txSvc
+}
+
+// issues a pass for approved leave of a hostel resident, day scholars need none
+func (s *GatePassService) Issue(leave *models.LeaveRequest) error {
+	if leave.Student.Hostel == "" {
+		return nil
+	}
+
+	return s.repo.Create(&models.GatePass{
+		LeaveID:   leave.ID,
+		StudentID: leave.StudentID,
+		Hostel:    leave.Student.Hostel,
+		Code:      s.signer.Sign(fmt.Sprintf("%d:%d", leave.ID, leave.StudentID)),
+		Status:    models.GatePassIssued,
+	})
+}
+
+// voids an unused pass when its leave is cancelled
+func (s *GatePassService) Revoke(leaveID uint) error {
+	pass, err := s.repo.FindByLeaveID(leaveID)
+	// Day scholars and retroactive leave have no pass to void
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if pass.Status != models.GatePassIssued {
+		return nil
+	}
+
+	pass.Status = models.GatePassRevoked
+	return s.repo.Update(pass)
+}
+
+func (s *GatePassService) GetForLeave(leaveID, studentID uint) (*models.GatePass, error) {
+	pass, err := s.repo.FindByLeaveID(leaveID)
+	if err != nil || pass.StudentID != studentID {
+		return nil, models.ErrGatePassNotFound
+	}
+	return pass, nil
+}
+
+// records the student leaving campus, only during the leave itself
+func (s *GatePassService) CheckOut(code string, guardID uint) (*models.GatePass, error) {
+	pass, err := s.findByCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if pass.Status != models.GatePassIssued || pass.Leave.Status != models.LeaveStatusApproved {
+		return nil, models.ErrGatePassNotUsable
+	}
+
+	now := time.Now()
+	if now.Before(models.DateOnly(pass.Leave.StartDate)) || !now.Before(models.ReturnDeadline(pass.Leave)) {
+		return nil, models.ErrGatePassNotValidNow
+	}
+
+	pass.Status = models.GatePassOut
+	pass.CheckedOutAt = &now
+	pass.CheckedOutBy = &guardID
+
+	if err := s.repo.Update(pass); err != nil {
+		return nil, err
+	}
+	return pass, nil
+}
+
+// records the student's return, flagging it when past the leave's end date
+func (s *GatePassService) CheckIn(code string, guardID uint) (*models.GatePass, error) {
+	pass, err := s.findByCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if pass.Status != models.GatePassOut {
+		return nil, models.ErrGatePassNotUsable
+	}
+
+	now := time.Now()
+	pass.Status = models.GatePassReturned
+	pass.CheckedInAt = &now
+	pass.CheckedInBy = &guardID
+	pass.LateReturn = now.After(models.ReturnDeadline(pass.Leave))
+
+	if err := s.repo.Update(pass); err != nil {
+		return nil, err
+	}
+	return pass, nil
+}
+
+// wardens see their own hostel, admins any hostel or all of them
+func (s *GatePassService) CurrentlyOut(viewerID uint, hostel string) ([]models.GatePass, error) {
+	viewer, err := s.userRepo.FindByID(viewerID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	if viewer.Role == models.RoleWarden {
+		hostel = viewer.Hostel
+	}
+
+	passes, err := s.repo.FindOut(hostel)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range passes {
+		passes[i].Code = ""
+		passes[i].Overdue = passes[i].Leave != nil && now.After(models.ReturnDeadline(passes[i].Leave))
+	}
+	return passes, nil
+}
+
+func (s *GatePassService) findByCode(code string) (*models.GatePass, error) {
+	if _, err := s.signer.Verify(code); err != nil {
+		return nil, models.ErrInvalidGatePass
+	}
+
+	pass, err := s.repo.FindByCode(code)
+	if err != nil || pass.Leave == nil {
+		return nil, models.ErrInvalidGatePass
+	}
+	return pass, nil
+}
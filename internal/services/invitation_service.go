@@ -0,0 +1,145 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/auth"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+)
+
+// InvitationService issues and redeems the signed invitations staff accounts register with
+type InvitationService struct {
+	repo            *repositories.InvitationRepository
+	userRepo        *repositories.UserRepository
+	notificationSvc *NotificationService
+	signer          *auth.Signer
+	cfg             core.InvitationConfig
+	baseURL         string
+}
+
+func NewInvitationService(
+	repo *repositories.InvitationRepository,
+	userRepo *repositories.UserRepository,
+	notificationSvc *NotificationService,
+	signer *auth.Signer,
+	cfg core.InvitationConfig,
+	baseURL string,
+) *InvitationService {
+	return &InvitationService{
+		repo:            repo,
+		userRepo:        userRepo,
+		notificationSvc: notificationSvc,
+		signer:          signer,
+		cfg:             cfg,
+		baseURL:         baseURL,
+	}
+}
+
+// records the invitation and emails the invitee a link carrying its token
+func (s *InvitationService) Create(adminID uint, req models.CreateInvitationRequest) (*models.CreatedInvitation, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if existing, _ := s.userRepo.FindByEmail(email); existing != nil {
+		return nil, models.ErrEmailTaken
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	invitation := &models.Invitation{
+		Email:       email,
+		Role:        models.Role(req.Role),
+		Dept:        req.Dept,
+		Hostel:      req.Hostel,
+		InvitedByID: adminID,
+		Nonce:       hex.EncodeToString(nonce),
+		ExpiresAt:   time.Now().Add(s.cfg.TTL),
+	}
+	if err := s.repo.Create(invitation); err != nil {
+		return nil, err
+	}
+
+	token := s.signer.Sign(fmt.Sprintf("%d:%s:%d", invitation.ID, invitation.Nonce, invitation.ExpiresAt.Unix()))
+	link := s.baseURL + "/register?invitation=" + url.QueryEscape(token)
+	s.notificationSvc.SendInvitation(invitation, link)
+
+	return &models.CreatedInvitation{Invitation: invitation, Token: token, Link: link}, nil
+}
+
+func (s *InvitationService) List() ([]models.Invitation, error) {
+	return s.repo.FindAll()
+}
+
+// withdraws an invitation that has not been accepted yet
+func (s *InvitationService) Revoke(id uint) error {
+	invitation, err := s.repo.FindByID(id)
+	if err != nil {
+		return models.ErrInvitationNotFound
+	}
+	if invitation.AcceptedAt != nil {
+		return models.ErrInvalidInvitation
+	}
+	if invitation.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	invitation.RevokedAt = &now
+	return s.repo.Update(invitation)
+}
+
+// fails unless the role may sign up without an invitation
+func (s *InvitationService) CheckSelfRegistration(role models.Role) error {
+	if role != models.RoleStudent || !s.cfg.StudentSelfRegistration {
+		return models.ErrInvitationRequired
+	}
+	return nil
+}
+
+// checks the token belongs to an open invitation for the email and claims it, so it works once
+func (s *InvitationService) Redeem(token, email string) (*models.Invitation, error) {
+	payload, err := s.signer.Verify(token)
+	if err != nil {
+		return nil, models.ErrInvalidInvitation
+	}
+
+	parts := strings.Split(payload, ":")
+	if len(parts) != 3 {
+		return nil, models.ErrInvalidInvitation
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, models.ErrInvalidInvitation
+	}
+
+	invitation, err := s.repo.FindByID(uint(id))
+	if err != nil || invitation.Nonce != parts[1] || !strings.EqualFold(invitation.Email, strings.TrimSpace(email)) {
+		return nil, models.ErrInvalidInvitation
+	}
+
+	claimed, err := s.repo.Claim(invitation.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, models.ErrInvalidInvitation
+	}
+	return invitation, nil
+}
+
+// links a redeemed invitation to the account it created, or frees it again when that failed
+func (s *InvitationService) Complete(invitation *models.Invitation, user *models.User) error {
+	if user == nil {
+		return s.repo.Release(invitation.ID)
+	}
+	return s.repo.SetAcceptedUser(invitation.ID, user.ID)
+}
@@ -1,37 +1,83 @@
 package services
 
 import (
+	"errors"
+	"log"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/prannvs/campus-leave-system/internal/core"
 	"github.com/prannvs/campus-leave-system/internal/models"
 	"github.com/prannvs/campus-leave-system/internal/repositories"
+	"gorm.io/gorm"
 )
 
 type LeaveService struct {
 	leaveRepo       *repositories.LeaveRepository
+	eventRepo       *repositories.LeaveEventRepository
 	attendanceRepo  *repositories.AttendanceRepository
+	userRepo        *repositories.UserRepository
 	notificationSvc *NotificationService
+	balanceSvc      *BalanceService
+	workflowSvc     *WorkflowService
+	attachmentSvc   *AttachmentService
+	calendarSvc     *CalendarService
+	gatePassSvc     *GatePassService
+	delegationSvc   *DelegationService
+	policySvc       *PolicyService
+	leaveTypeSvc    *LeaveTypeService
+	consentSvc      *ConsentService
+	timetable       core.TimetableConfig
+	sla             core.SLAConfig
+	// side effects held back until the surrounding transaction commits
+	effects *[]func(*LeaveService)
 }
 
 func NewLeaveService(
 	leaveRepo *repositories.LeaveRepository,
+	eventRepo *repositories.LeaveEventRepository,
 	attendanceRepo *repositories.AttendanceRepository,
+	userRepo *repositories.UserRepository,
 	notificationSvc *NotificationService,
+	balanceSvc *BalanceService,
+	workflowSvc *WorkflowService,
+	attachmentSvc *AttachmentService,
+	calendarSvc *CalendarService,
+	gatePassSvc *GatePassService,
+	delegationSvc *DelegationService,
+	policySvc *PolicyService,
+	leaveTypeSvc *LeaveTypeService,
+	consentSvc *ConsentService,
+	timetable core.TimetableConfig,
+	sla core.SLAConfig,
 ) *LeaveService {
 	return &LeaveService{
 		leaveRepo:       leaveRepo,
+		eventRepo:       eventRepo,
 		attendanceRepo:  attendanceRepo,
+		userRepo:        userRepo,
 		notificationSvc: notificationSvc,
+		balanceSvc:      balanceSvc,
+		workflowSvc:     workflowSvc,
+		attachmentSvc:   attachmentSvc,
+		calendarSvc:     calendarSvc,
+		gatePassSvc:     gatePassSvc,
+		delegationSvc:   delegationSvc,
+		policySvc:       policySvc,
+		leaveTypeSvc:    leaveTypeSvc,
+		consentSvc:      consentSvc,
+		timetable:       timetable,
+		sla:             sla,
 	}
 }
 
 func (s *LeaveService) ApplyLeave(studentID uint, req models.ApplyLeaveRequest) (*models.LeaveRequest, error) {
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
-	if err != nil {
+	if _, err := s.leaveTypeSvc.Require(models.LeaveType(req.LeaveType)); err != nil {
 		return nil, err
 	}
 
-	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	startDate, endDate, err := parseLeaveDates(req)
 	if err != nil {
 		return nil, err
 	}
@@ -44,12 +90,34 @@ func (s *LeaveService) ApplyLeave(studentID uint, req models.ApplyLeaveRequest)
 		EndDate:   endDate,
 		Status:    models.LeaveStatusPending,
 	}
+	leave.Retroactive = isRetroactive(leave)
+
+	if err := s.setSession(leave, req); err != nil {
+		return nil, err
+	}
+
+	if err := setRecurrence(leave, req.Recurrence); err != nil {
+		return nil, err
+	}
 
 	if err := leave.Validate(); err != nil {
 		return nil, err
 	}
 
-	overlaps, err := s.leaveRepo.CheckOverlapping(studentID, startDate, endDate, 0)
+	student, err := s.userRepo.FindByID(studentID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	if err := s.policySvc.Evaluate(leave, student); err != nil {
+		return nil, err
+	}
+
+	if err := s.countDays(leave); err != nil {
+		return nil, err
+	}
+
+	overlaps, err := s.leaveRepo.CheckOverlapping(leave)
 	if err != nil {
 		return nil, err
 	}
@@ -57,74 +125,1461 @@ func (s *LeaveService) ApplyLeave(studentID uint, req models.ApplyLeaveRequest)
 		return nil, models.ErrOverlappingLeave
 	}
 
+	if err := s.balanceSvc.CheckAvailable(leave); err != nil {
+		return nil, err
+	}
+
+	if err := s.consentSvc.CheckGuardian(leave, student); err != nil {
+		return nil, err
+	}
+
+	steps, err := s.workflowSvc.StepsForLeave(leave, student)
+	if err != nil {
+		return nil, err
+	}
+	leave.CurrentStep = steps[0].StepOrder
+	leave.AwaitingRole = steps[0].ApproverRole
+	s.startStep(leave)
+
 	if err := s.leaveRepo.Create(leave); err != nil {
 		return nil, err
 	}
 
+	if err := s.recordEvent(leave, "", models.LeaveEvent{Action: models.LeaveEventSubmitted, ActorID: &studentID}); err != nil {
+		return nil, err
+	}
+
+	if err := s.consentSvc.Request(leave, student); err != nil {
+		return nil, err
+	}
+
 	return leave, nil
 }
 
 func (s *LeaveService) ApproveLeave(leaveID, approverID uint, status models.LeaveStatus, remarks *string) error {
+	return s.inTx(func(txSvc *LeaveService) error {
+		if err := txSvc.leaveRepo.Lock(leaveID); err != nil {
+			return models.ErrLeaveNotFound
+		}
+		return txSvc.decide(leaveID, approverID, status, remarks)
+	})
+}
+
+// decides the leave's current step; the caller holds the request's row
+func (s *LeaveService) decide(leaveID, approverID uint, status models.LeaveStatus, remarks *string) error {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil {
+		return models.ErrLeaveNotFound
+	}
+
+	if !leave.Status.IsOpen() {
+		return models.ErrLeaveNotPending
+	}
+
+	approver, err := s.userRepo.FindByID(approverID)
+	if err != nil {
+		return models.ErrUserNotFound
+	}
+
+	onBehalfOf, err := s.resolveAuthority(leave, approver)
+	if err != nil {
+		return err
+	}
+
+	var next *models.ApprovalStep
+	if status == models.LeaveStatusApproved {
+		if err := s.attachmentSvc.CheckRequired(leave); err != nil {
+			return err
+		}
+
+		// Extensions are a single decision by the assigned approver
+		if !leave.IsExtension() {
+			if next, err = s.workflowSvc.NextStep(leave); err != nil {
+				return err
+			}
+		}
+
+		// Only the decision that lets the student leave has to wait for the guardian
+		if next == nil {
+			if err := s.consentSvc.Check(leave); err != nil {
+				return err
+			}
+		}
+	}
+
+	approval := &models.LeaveApproval{
+		LeaveID:      leave.ID,
+		Step:         leave.CurrentStep,
+		ApproverID:   approverID,
+		ApproverRole: approver.Role,
+		Decision:     status,
+		Remarks:      remarks,
+	}
+	if onBehalfOf != nil {
+		approval.OnBehalfOfID = &onBehalfOf.ID
+		approval.ApproverRole = onBehalfOf.Role
+	}
+	if err := s.workflowSvc.RecordApproval(approval); err != nil {
+		return err
+	}
+
+	// Hand over to the next approver in the chain
+	if next != nil {
+		from := leave.Status
+		if err := leave.TransitionTo(models.LeaveStatusInReview); err != nil {
+			return err
+		}
+		leave.CurrentStep = next.StepOrder
+		leave.AwaitingRole = next.ApproverRole
+		s.startStep(leave)
+		if err := s.leaveRepo.Update(leave); err != nil {
+			return err
+		}
+		return s.recordEvent(leave, from, models.LeaveEvent{
+			Action:       models.LeaveEventForwarded,
+			ActorID:      &approverID,
+			OnBehalfOfID: approval.OnBehalfOfID,
+			Remarks:      remarks,
+		})
+	}
+
+	return s.finalize(leave, &approverID, approval.OnBehalfOfID, status, remarks)
+}
+
+// decides each selected request in its own transaction and reports how each one went
+func (s *LeaveService) BulkDecide(approverID uint, req models.BulkDecisionRequest) ([]models.BulkDecisionResult, error) {
+	ids := req.IDs
+	if len(ids) == 0 {
+		if req.Filter == nil {
+			return nil, models.ErrBulkSelectionRequired
+		}
+
+		var err error
+		ids, err = s.pendingMatching(approverID, req.Filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	status := models.LeaveStatus(req.Status)
+	results := make([]models.BulkDecisionResult, 0, len(ids))
+	for _, id := range ids {
+		err := s.ApproveLeave(id, approverID, status, req.Remarks)
+		results = append(results, bulkResult(id, status, err))
+	}
+	return results, nil
+}
+
+// ids from the approver's queue that match the filter
+func (s *LeaveService) pendingMatching(approverID uint, filter *models.BulkDecisionFilter) ([]uint, error) {
+	var from, to time.Time
+	var err error
+	if filter.StartFrom != "" {
+		if from, err = time.Parse("2006-01-02", filter.StartFrom); err != nil {
+			return nil, err
+		}
+	}
+	if filter.StartTo != "" {
+		if to, err = time.Parse("2006-01-02", filter.StartTo); err != nil {
+			return nil, err
+		}
+	}
+
+	pending, err := s.GetPendingLeaves(approverID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	for _, leave := range pending {
+		start := models.DateOnly(leave.StartDate)
+		switch {
+		case filter.LeaveType != "" && string(leave.LeaveType) != filter.LeaveType:
+		case !from.IsZero() && start.Before(from):
+		case !to.IsZero() && start.After(to):
+		default:
+			ids = append(ids, leave.ID)
+		}
+	}
+	return ids, nil
+}
+
+// copy of the service whose writes go through the transaction
+func (s *LeaveService) withTx(tx *gorm.DB) *LeaveService {
+	txSvc := *s
+	txSvc.leaveRepo = s.leaveRepo.WithTx(tx)
+	txSvc.eventRepo = s.eventRepo.WithTx(tx)
+	txSvc.balanceSvc = s.balanceSvc.WithTx(tx)
+	txSvc.workflowSvc = s.workflowSvc.WithTx(tx)
+	txSvc.gatePassSvc = s.gatePassSvc.WithTx(tx)
+	txSvc.attendanceRepo = s.attendanceRepo.WithTx(tx)
+	return &txSvc
+}
+
+// runs fn in a transaction and starts the side effects it queued only once that commits
+func (s *LeaveService) inTx(fn func(txSvc *LeaveService) error) error {
+	var effects []func(*LeaveService)
+	err := s.leaveRepo.Transaction(func(tx *gorm.DB) error {
+		txSvc := s.withTx(tx)
+		txSvc.effects = &effects
+		return fn(txSvc)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, effect := range effects {
+		go effect(s)
+	}
+	return nil
+}
+
+// starts a side effect in the background, or queues it while a transaction is open;
+// it is handed the service outside the transaction, whose handle is gone by then
+func (s *LeaveService) afterCommit(effect func(*LeaveService)) {
+	if s.effects == nil {
+		go effect(s)
+		return
+	}
+	*s.effects = append(*s.effects, effect)
+}
+
+func bulkResult(leaveID uint, status models.LeaveStatus, err error) models.BulkDecisionResult {
+	result := models.BulkDecisionResult{LeaveID: leaveID}
+	switch {
+	case err == nil && status == models.LeaveStatusApproved:
+		result.Outcome = models.BulkOutcomeApproved
+	case err == nil:
+		result.Outcome = models.BulkOutcomeRejected
+	case errors.Is(err, models.ErrLeaveNotPending):
+		result.Outcome = models.BulkOutcomeAlreadyDecided
+	case errors.Is(err, models.ErrNotAwaitingApprover), errors.Is(err, models.ErrOutOfScope):
+		result.Outcome = models.BulkOutcomeOutOfScope
+	case errors.Is(err, models.ErrLeaveNotFound):
+		result.Outcome = models.BulkOutcomeNotFound
+	default:
+		result.Outcome = models.BulkOutcomeFailed
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// approves an Emergency request that has sat past its SLA as the system rather than any person;
+// only a request on the last step of its chain that passes the checks a person's approval needs
+func (s *LeaveService) AutoApprove(leaveID uint) error {
+	return s.inTx(func(txSvc *LeaveService) error {
+		if err := txSvc.leaveRepo.Lock(leaveID); err != nil {
+			return models.ErrLeaveNotFound
+		}
+		return txSvc.autoApprove(leaveID)
+	})
+}
+
+func (s *LeaveService) autoApprove(leaveID uint) error {
 	leave, err := s.leaveRepo.FindByID(leaveID)
 	if err != nil {
 		return models.ErrLeaveNotFound
 	}
 
-	if leave.Status != models.LeaveStatusPending {
-		return models.ErrInvalidRole
+	if !leave.Status.IsOpen() {
+		return models.ErrLeaveNotPending
+	}
+
+	if err := s.attachmentSvc.CheckRequired(leave); err != nil {
+		return err
+	}
+	if !leave.IsExtension() {
+		next, err := s.workflowSvc.NextStep(leave)
+		if err != nil {
+			return err
+		}
+		if next != nil {
+			return models.ErrStepsRemaining
+		}
+	}
+	if err := s.consentSvc.Check(leave); err != nil {
+		return err
 	}
 
-	leave.Status = status
-	leave.ApprovedBy = &approverID
+	remarks := "Auto-approved after the approval deadline passed"
+	return s.finalize(leave, nil, nil, models.LeaveStatusApproved, &remarks)
+}
+
+// closes the request and applies the side effects of the final decision; approverID is nil
+// when the system decided
+func (s *LeaveService) finalize(leave *models.LeaveRequest, approverID, onBehalfOfID *uint, status models.LeaveStatus, remarks *string) error {
+	from := leave.Status
+	if err := leave.TransitionTo(status); err != nil {
+		return err
+	}
+	leave.ApprovedBy = approverID
+	leave.OnBehalfOfID = onBehalfOfID
 	leave.Remarks = remarks
+	leave.AwaitingRole = ""
+	leave.AssignedApproverID = nil
 
 	if err := s.leaveRepo.Update(leave); err != nil {
 		return err
 	}
 
+	action := models.LeaveEventRejected
+	switch {
+	case status == models.LeaveStatusApproved && approverID == nil:
+		action = models.LeaveEventAutoApproved
+	case status == models.LeaveStatusApproved:
+		action = models.LeaveEventApproved
+	}
+	if err := s.recordEvent(leave, from, models.LeaveEvent{
+		Action:       action,
+		ActorID:      approverID,
+		OnBehalfOfID: onBehalfOfID,
+		Remarks:      remarks,
+	}); err != nil {
+		return err
+	}
+
 	if status == models.LeaveStatusApproved {
-		go s.markLeaveAttendance(leave, approverID)
+		// Pending requests hold no days, so the quota is checked again as the days are spent
+		if err := s.balanceSvc.LockStudent(leave.StudentID); err != nil {
+			return err
+		}
+		if err := s.balanceSvc.CheckAvailable(leave); err != nil {
+			return err
+		}
+
+		if leave.IsExtension() {
+			if err := s.applyExtension(leave, approverID); err != nil {
+				return err
+			}
+		} else {
+			if err := s.balanceSvc.Debit(leave); err != nil {
+				return err
+			}
+			// Retroactive leave has already been taken, so there is no gate to pass, and a
+			// single-trip pass cannot cover the many trips of a series
+			if !leave.Retroactive && !leave.IsRecurring() {
+				if err := s.gatePassSvc.Issue(leave); err != nil {
+					return err
+				}
+			}
+			s.afterCommit(func(root *LeaveService) {
+				root.markLeaveAttendance(leave, leave.StartDate, approverID)
+			})
+		}
 	}
 
-	go s.notificationSvc.SendLeaveStatusNotification(leave)
+	s.afterCommit(func(root *LeaveService) {
+		root.notificationSvc.SendLeaveStatusNotification(leave)
+	})
 
 	return nil
 }
 
-func (s *LeaveService) markLeaveAttendance(leave *models.LeaveRequest, markerID uint) {
-	currentDate := leave.StartDate
-	for !currentDate.After(leave.EndDate) {
-		attendance := &models.Attendance{
-			StudentID: leave.StudentID,
-			Date:      currentDate,
-			Present:   false,
-			MarkedBy:  markerID,
+// edits a request that no approver has acted on yet
+func (s *LeaveService) UpdateLeave(leaveID, studentID uint, req models.ApplyLeaveRequest) (*models.LeaveRequest, error) {
+	var leave *models.LeaveRequest
+	err := s.inTx(func(txSvc *LeaveService) error {
+		if err := txSvc.leaveRepo.Lock(leaveID); err != nil {
+			return models.ErrLeaveNotFound
 		}
-		s.attendanceRepo.Create(attendance)
-		currentDate = currentDate.AddDate(0, 0, 1)
+		var err error
+		leave, err = txSvc.update(leaveID, studentID, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	// The guardian agreed to the old dates, so ask again
+	if err := s.consentSvc.Request(leave, &leave.Student); err != nil {
+		return nil, err
+	}
+	return leave, nil
 }
 
-func (s *LeaveService) GetMyLeaves(studentID uint) ([]models.LeaveRequest, error) {
-	return s.leaveRepo.FindByStudentID(studentID)
+// applies the edit; the caller holds the request's row
+func (s *LeaveService) update(leaveID, studentID uint, req models.ApplyLeaveRequest) (*models.LeaveRequest, error) {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil || leave.StudentID != studentID {
+		return nil, models.ErrLeaveNotFound
+	}
+
+	if leave.IsExtension() {
+		return nil, models.ErrExtensionNotEditable
+	}
+
+	if leave.Status != models.LeaveStatusPending || len(leave.Approvals) > 0 {
+		return nil, models.ErrLeaveNotEditable
+	}
+
+	if _, err := s.leaveTypeSvc.Require(models.LeaveType(req.LeaveType)); err != nil {
+		return nil, err
+	}
+
+	startDate, endDate, err := parseLeaveDates(req)
+	if err != nil {
+		return nil, err
+	}
+
+	leave.LeaveType = models.LeaveType(req.LeaveType)
+	leave.Reason = req.Reason
+	leave.StartDate = startDate
+	leave.EndDate = endDate
+	leave.Retroactive = isRetroactive(leave)
+
+	if err := s.setSession(leave, req); err != nil {
+		return nil, err
+	}
+
+	if err := setRecurrence(leave, req.Recurrence); err != nil {
+		return nil, err
+	}
+
+	if err := leave.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.policySvc.Evaluate(leave, &leave.Student); err != nil {
+		return nil, err
+	}
+
+	if err := s.countDays(leave); err != nil {
+		return nil, err
+	}
+
+	overlaps, err := s.leaveRepo.CheckOverlapping(leave)
+	if err != nil {
+		return nil, err
+	}
+	if overlaps {
+		return nil, models.ErrOverlappingLeave
+	}
+
+	if err := s.balanceSvc.CheckAvailable(leave); err != nil {
+		return nil, err
+	}
+
+	if err := s.consentSvc.CheckGuardian(leave, &leave.Student); err != nil {
+		return nil, err
+	}
+
+	// The leave type may have changed, so restart the approval chain
+	steps, err := s.workflowSvc.StepsForLeave(leave, &leave.Student)
+	if err != nil {
+		return nil, err
+	}
+	leave.CurrentStep = steps[0].StepOrder
+	leave.AwaitingRole = steps[0].ApproverRole
+	s.startStep(leave)
+
+	if err := s.leaveRepo.Update(leave); err != nil {
+		return nil, err
+	}
+
+	if err := s.leaveRepo.ReplaceOccurrences(leave); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordEvent(leave, leave.Status, models.LeaveEvent{Action: models.LeaveEventEdited, ActorID: &studentID}); err != nil {
+		return nil, err
+	}
+
+	return leave, nil
 }
 
-func (s *LeaveService) GetPendingLeaves() ([]models.LeaveRequest, error) {
-	return s.leaveRepo.FindPending()
+// pulls back a request that has not been decided yet
+func (s *LeaveService) WithdrawLeave(leaveID, studentID uint) error {
+	return s.inTx(func(txSvc *LeaveService) error {
+		if err := txSvc.leaveRepo.Lock(leaveID); err != nil {
+			return models.ErrLeaveNotFound
+		}
+		return txSvc.withdraw(leaveID, studentID)
+	})
 }
 
-func (s *LeaveService) GetByStatus(status models.LeaveStatus, page, pageSize int) ([]models.LeaveRequest, int64, error) {
-	return s.leaveRepo.FindByStatus(status, page, pageSize)
+// withdraws the request; the caller holds its row
+func (s *LeaveService) withdraw(leaveID, studentID uint) error {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil || leave.StudentID != studentID {
+		return models.ErrLeaveNotFound
+	}
+
+	if !leave.Status.IsOpen() {
+		return models.ErrLeaveNotPending
+	}
+
+	from := leave.Status
+	if err := leave.TransitionTo(models.LeaveStatusWithdrawn); err != nil {
+		return err
+	}
+	leave.AwaitingRole = ""
+
+	if err := s.leaveRepo.Update(leave); err != nil {
+		return err
+	}
+
+	return s.recordEvent(leave, from, models.LeaveEvent{Action: models.LeaveEventWithdrawn, ActorID: &studentID})
 }
 
-func (s *LeaveService) GetByID(id uint) (*models.LeaveRequest, error) {
-	return s.leaveRepo.FindByID(id)
+// parks the request with the student until they answer the approver's question
+func (s *LeaveService) RequestInfo(leave *models.LeaveRequest, approver *models.User) error {
+	if !leave.Status.IsOpen() {
+		return models.ErrLeaveNotPending
+	}
+
+	onBehalfOf, err := s.resolveAuthority(leave, approver)
+	if err != nil {
+		return err
+	}
+
+	// The SLA clock stops while the request is with the student
+	from := leave.Status
+	if err := leave.TransitionTo(models.LeaveStatusNeedsInfo); err != nil {
+		return err
+	}
+	leave.DueAt = nil
+	leave.Escalated = false
+	leave.EscalatedAt = nil
+
+	if err := s.leaveRepo.Update(leave); err != nil {
+		return err
+	}
+
+	event := models.LeaveEvent{Action: models.LeaveEventInfoRequested, ActorID: &approver.ID}
+	if onBehalfOf != nil {
+		event.OnBehalfOfID = &onBehalfOf.ID
+	}
+	return s.recordEvent(leave, from, event)
+}
+
+// returns the request to the approver's queue once the student has replied
+func (s *LeaveService) ResumeReview(leave *models.LeaveRequest) error {
+	if leave.Status != models.LeaveStatusNeedsInfo {
+		return nil
+	}
+
+	to := models.LeaveStatusPending
+	if len(leave.Approvals) > 0 {
+		to = models.LeaveStatusInReview
+	}
+
+	from := leave.Status
+	if err := leave.TransitionTo(to); err != nil {
+		return err
+	}
+	s.startStep(leave)
+
+	if err := s.leaveRepo.Update(leave); err != nil {
+		return err
+	}
+
+	return s.recordEvent(leave, from, models.LeaveEvent{Action: models.LeaveEventResumed, ActorID: &leave.StudentID})
 }
 
-func (s *LeaveService) Delete(id uint) error {
-	return s.leaveRepo.Delete(id)
+// the approvers who may act on the request's current step
+func (s *LeaveService) AwaitingApprovers(leave *models.LeaveRequest) ([]models.User, error) {
+	if leave.AssignedApproverID != nil {
+		approver, err := s.userRepo.FindByID(*leave.AssignedApproverID)
+		if err != nil {
+			return nil, err
+		}
+		return []models.User{*approver}, nil
+	}
+
+	if leave.AwaitingRole == "" {
+		return nil, nil
+	}
+
+	users, err := s.userRepo.FindByRole(leave.AwaitingRole)
+	if err != nil {
+		return nil, err
+	}
+
+	var approvers []models.User
+	for _, user := range users {
+		if user.Scope().Covers(&leave.Student) {
+			approvers = append(approvers, user)
+		}
+	}
+	return approvers, nil
 }
 
-func (s *LeaveService) GetLeaveStats(startDate, endDate time.Time) (map[string]interface{}, error) {
-	return s.leaveRepo.GetLeaveStats(startDate, endDate)
+// the approvers of the step after the request's current one, who are alerted when it stalls;
+// nil when the current step is the last
+func (s *LeaveService) NextApprovers(leave *models.LeaveRequest) ([]models.User, error) {
+	if leave.IsExtension() {
+		return nil, nil
+	}
+
+	next, err := s.workflowSvc.NextStep(leave)
+	if err != nil || next == nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.FindByRole(next.ApproverRole)
+	if err != nil {
+		return nil, err
+	}
+
+	var approvers []models.User
+	for _, user := range users {
+		if user.Scope().Covers(&leave.Student) {
+			approvers = append(approvers, user)
+		}
+	}
+	return approvers, nil
+}
+
+// loads the request along with the viewer, failing as not found when they may not see it
+func (s *LeaveService) ViewLeave(leaveID, viewerID uint) (*models.LeaveRequest, *models.User, error) {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil {
+		return nil, nil, models.ErrLeaveNotFound
+	}
+
+	viewer, err := s.userRepo.FindByID(viewerID)
+	if err != nil {
+		return nil, nil, models.ErrUserNotFound
+	}
+
+	delegators, err := s.delegationSvc.ActiveDelegators(viewerID, models.DelegationScopeLeaveApproval)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !canViewLeave(leave, viewer, delegators) {
+		return nil, nil, models.ErrLeaveNotFound
+	}
+	return leave, viewer, nil
+}
+
+// cancels approved leave that has not started, returning the days and clearing its attendance
+func (s *LeaveService) CancelLeave(leaveID, studentID uint) error {
+	return s.inTx(func(txSvc *LeaveService) error {
+		if err := txSvc.leaveRepo.Lock(leaveID); err != nil {
+			return models.ErrLeaveNotFound
+		}
+		return txSvc.cancel(leaveID, studentID)
+	})
+}
+
+// cancels the leave and undoes what its approval granted; the caller holds its row
+func (s *LeaveService) cancel(leaveID, studentID uint) error {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil || leave.StudentID != studentID {
+		return models.ErrLeaveNotFound
+	}
+
+	if leave.IsExtension() {
+		return models.ErrExtensionNotEditable
+	}
+
+	if leave.Status != models.LeaveStatusApproved {
+		return models.ErrLeaveNotCancellable
+	}
+
+	if leave.StartDate.Before(time.Now().Truncate(24 * time.Hour)) {
+		return models.ErrLeaveAlreadyStarted
+	}
+
+	// The days go back to the quota, so balance checks for the student wait on this
+	if err := s.balanceSvc.LockStudent(leave.StudentID); err != nil {
+		return err
+	}
+
+	from := leave.Status
+	if err := leave.TransitionTo(models.LeaveStatusCancelled); err != nil {
+		return err
+	}
+
+	if err := s.leaveRepo.Update(leave); err != nil {
+		return err
+	}
+
+	if err := s.recordEvent(leave, from, models.LeaveEvent{Action: models.LeaveEventCancelled, ActorID: &studentID}); err != nil {
+		return err
+	}
+
+	if err := s.closeExtensions(leave, studentID); err != nil {
+		return err
+	}
+
+	if err := s.balanceSvc.Credit(leave); err != nil {
+		return err
+	}
+
+	if err := s.attendanceRepo.ReleaseByLeave(leave.ID); err != nil {
+		return err
+	}
+
+	if err := s.gatePassSvc.Revoke(leave.ID); err != nil {
+		return err
+	}
+
+	s.afterCommit(func(root *LeaveService) {
+		root.notificationSvc.SendLeaveStatusNotification(leave)
+	})
+
+	return nil
+}
+
+// extensions are folded into their parent, so they close with it
+func (s *LeaveService) closeExtensions(parent *models.LeaveRequest, actorID uint) error {
+	extensions, err := s.leaveRepo.FindActiveExtensions(parent.ID)
+	if err != nil {
+		return err
+	}
+	for i := range extensions {
+		extension := &extensions[i]
+		from := extension.Status
+		to, action := models.LeaveStatusCancelled, models.LeaveEventCancelled
+		if from.IsOpen() {
+			to, action = models.LeaveStatusWithdrawn, models.LeaveEventWithdrawn
+		}
+		if err := extension.TransitionTo(to); err != nil {
+			return err
+		}
+		extension.AwaitingRole = ""
+		extension.AssignedApproverID = nil
+		if err := s.leaveRepo.Update(extension); err != nil {
+			return err
+		}
+		if err := s.recordEvent(extension, from, models.LeaveEvent{Action: action, ActorID: &actorID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cancels one upcoming occurrence of a series
+func (s *LeaveService) CancelOccurrence(leaveID, occurrenceID, studentID uint) error {
+	return s.inTx(func(txSvc *LeaveService) error {
+		if err := txSvc.leaveRepo.Lock(leaveID); err != nil {
+			return models.ErrLeaveNotFound
+		}
+		return txSvc.cancelOccurrence(leaveID, occurrenceID, studentID)
+	})
+}
+
+// the caller holds the series' row, so the occurrences read here are current
+func (s *LeaveService) cancelOccurrence(leaveID, occurrenceID, studentID uint) error {
+	leave, err := s.recurringLeave(leaveID, studentID)
+	if err != nil {
+		return err
+	}
+
+	for i := range leave.Occurrences {
+		occurrence := &leave.Occurrences[i]
+		if occurrence.ID != occurrenceID || occurrence.IsCancelled() {
+			continue
+		}
+		if occurrence.StartDate.Before(models.DateOnly(time.Now())) {
+			return models.ErrLeaveAlreadyStarted
+		}
+		return s.cancelOccurrences(leave, studentID, []*models.LeaveOccurrence{occurrence})
+	}
+	return models.ErrOccurrenceNotFound
+}
+
+// cancels every occurrence of a series that has not started yet
+func (s *LeaveService) CancelRemaining(leaveID, studentID uint) error {
+	return s.inTx(func(txSvc *LeaveService) error {
+		if err := txSvc.leaveRepo.Lock(leaveID); err != nil {
+			return models.ErrLeaveNotFound
+		}
+		return txSvc.cancelRemaining(leaveID, studentID)
+	})
+}
+
+// the caller holds the series' row, so the occurrences read here are current
+func (s *LeaveService) cancelRemaining(leaveID, studentID uint) error {
+	leave, err := s.recurringLeave(leaveID, studentID)
+	if err != nil {
+		return err
+	}
+
+	today := models.DateOnly(time.Now())
+	var remaining []*models.LeaveOccurrence
+	for i := range leave.Occurrences {
+		occurrence := &leave.Occurrences[i]
+		if !occurrence.IsCancelled() && !occurrence.StartDate.Before(today) {
+			remaining = append(remaining, occurrence)
+		}
+	}
+	if len(remaining) == 0 {
+		return models.ErrOccurrenceNotFound
+	}
+
+	return s.cancelOccurrences(leave, studentID, remaining)
+}
+
+// the student's series while it is still open or approved
+func (s *LeaveService) recurringLeave(leaveID, studentID uint) (*models.LeaveRequest, error) {
+	leave, err := s.leaveRepo.FindByID(leaveID)
+	if err != nil || leave.StudentID != studentID {
+		return nil, models.ErrLeaveNotFound
+	}
+
+	if !leave.IsRecurring() {
+		return nil, models.ErrNotRecurring
+	}
+
+	if !leave.Status.IsOpen() && leave.Status != models.LeaveStatusApproved {
+		return nil, models.ErrLeaveNotCancellable
+	}
+	return leave, nil
+}
+
+// drops the occurrences from the series, returning their days and attendance once approved,
+// and closes the series when none are left; the caller holds the series' row
+func (s *LeaveService) cancelOccurrences(leave *models.LeaveRequest, studentID uint, occurrences []*models.LeaveOccurrence) error {
+	if leave.Status == models.LeaveStatusApproved {
+		if err := s.balanceSvc.LockStudent(leave.StudentID); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	var days float64
+	var dates []time.Time
+	var cancelled []string
+	for _, occurrence := range occurrences {
+		occurrence.CancelledAt = &now
+		if err := s.leaveRepo.UpdateOccurrence(occurrence); err != nil {
+			return err
+		}
+
+		days += occurrence.Days
+		for day := models.DateOnly(occurrence.StartDate); !day.After(models.DateOnly(occurrence.EndDate)); day = day.AddDate(0, 0, 1) {
+			dates = append(dates, day)
+		}
+		cancelled = append(cancelled, occurrence.StartDate.Format("2006-01-02"))
+	}
+
+	if leave.Status == models.LeaveStatusApproved {
+		if err := s.balanceSvc.CreditOccurrences(leave, occurrences); err != nil {
+			return err
+		}
+		if err := s.attendanceRepo.ReleaseByLeaveDates(leave.ID, dates); err != nil {
+			return err
+		}
+	}
+
+	from := leave.Status
+	leave.Days -= days
+	active := leave.ActiveOccurrences()
+	if len(active) == 0 {
+		to := models.LeaveStatusCancelled
+		if from.IsOpen() {
+			to = models.LeaveStatusWithdrawn
+		}
+		if err := leave.TransitionTo(to); err != nil {
+			return err
+		}
+		leave.AwaitingRole = ""
+		leave.AssignedApproverID = nil
+	} else {
+		leave.EndDate = active[len(active)-1].EndDate
+	}
+
+	if err := s.leaveRepo.Update(leave); err != nil {
+		return err
+	}
+
+	remarks := "Cancelled occurrences starting " + strings.Join(cancelled, ", ")
+	if err := s.recordEvent(leave, from, models.LeaveEvent{
+		Action:  models.LeaveEventOccurrencesCancelled,
+		ActorID: &studentID,
+		Remarks: &remarks,
+	}); err != nil {
+		return err
+	}
+
+	if leave.Status != from {
+		s.afterCommit(func(root *LeaveService) {
+			root.notificationSvc.SendLeaveStatusNotification(leave)
+		})
+	}
+	return nil
+}
+
+// files an extension of approved leave, routed back to whoever approved it
+func (s *LeaveService) ExtendLeave(parentID, studentID uint, req models.ExtendLeaveRequest) (*models.LeaveRequest, error) {
+	parent, err := s.leaveRepo.FindByID(parentID)
+	if err != nil || parent.StudentID != studentID {
+		return nil, models.ErrLeaveNotFound
+	}
+
+	if parent.IsExtension() || parent.IsPartial() || parent.IsRecurring() || parent.Status != models.LeaveStatusApproved {
+		return nil, models.ErrLeaveNotExtendable
+	}
+
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if !endDate.After(parent.EndDate) {
+		return nil, models.ErrInvalidExtension
+	}
+
+	extensions, err := s.leaveRepo.FindActiveExtensions(parent.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, extension := range extensions {
+		if extension.Status.IsOpen() {
+			return nil, models.ErrExtensionPending
+		}
+	}
+
+	extension := &models.LeaveRequest{
+		StudentID:          studentID,
+		LeaveType:          parent.LeaveType,
+		Reason:             req.Reason,
+		StartDate:          parent.EndDate.AddDate(0, 0, 1),
+		EndDate:            endDate,
+		Status:             models.LeaveStatusPending,
+		ParentID:           &parent.ID,
+		CurrentStep:        1,
+		AssignedApproverID: parent.DecidedFor(),
+	}
+
+	if err := extension.Validate(); err != nil {
+		return nil, err
+	}
+	if extension.StartDate.Before(models.DateOnly(time.Now())) {
+		return nil, models.ErrPastDate
+	}
+
+	if err := s.countDays(extension); err != nil {
+		return nil, err
+	}
+
+	overlaps, err := s.leaveRepo.CheckOverlapping(extension)
+	if err != nil {
+		return nil, err
+	}
+	if overlaps {
+		return nil, models.ErrOverlappingLeave
+	}
+
+	if err := s.balanceSvc.CheckAvailable(extension); err != nil {
+		return nil, err
+	}
+
+	if parent.OnBehalfOf != nil {
+		extension.AwaitingRole = parent.OnBehalfOf.Role
+	} else if parent.Approver != nil {
+		extension.AwaitingRole = parent.Approver.Role
+	} else {
+		// Nobody to route back to, so fall back to the usual chain
+		steps, err := s.workflowSvc.StepsFor(parent.LeaveType, &parent.Student)
+		if err != nil {
+			return nil, err
+		}
+		extension.CurrentStep = steps[0].StepOrder
+		extension.AwaitingRole = steps[0].ApproverRole
+	}
+	s.startStep(extension)
+
+	if err := s.leaveRepo.Create(extension); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordEvent(extension, "", models.LeaveEvent{Action: models.LeaveEventSubmitted, ActorID: &studentID}); err != nil {
+		return nil, err
+	}
+
+	return extension, nil
+}
+
+// pushes out the parent's end date and covers the extra days
+func (s *LeaveService) applyExtension(extension *models.LeaveRequest, approverID *uint) error {
+	parent, err := s.leaveRepo.FindByID(*extension.ParentID)
+	if err != nil {
+		return models.ErrLeaveNotFound
+	}
+
+	parent.EndDate = extension.EndDate
+	parent.Days += extension.Days
+	if err := s.leaveRepo.Update(parent); err != nil {
+		return err
+	}
+
+	remarks := "Extended to " + extension.EndDate.Format("2006-01-02")
+	if err := s.recordEvent(parent, parent.Status, models.LeaveEvent{
+		Action:  models.LeaveEventExtended,
+		ActorID: approverID,
+		Remarks: &remarks,
+	}); err != nil {
+		return err
+	}
+
+	if err := s.balanceSvc.DebitExtension(parent, extension); err != nil {
+		return err
+	}
+
+	s.afterCommit(func(root *LeaveService) {
+		root.markLeaveAttendance(parent, extension.StartDate, approverID)
+	})
+
+	return nil
+}
+
+// checks that the approver may decide the leave's current step
+func authorizeApprover(leave *models.LeaveRequest, approver *models.User) error {
+	// Admins may act on any step of the chain
+	if approver.Role == models.RoleAdmin {
+		return nil
+	}
+
+	if leave.AssignedApproverID != nil {
+		if *leave.AssignedApproverID != approver.ID {
+			return models.ErrNotAwaitingApprover
+		}
+		return nil
+	}
+
+	if approver.Role != leave.AwaitingRole {
+		return models.ErrNotAwaitingApprover
+	}
+
+	if !approver.Scope().Covers(&leave.Student) {
+		return models.ErrOutOfScope
+	}
+	return nil
+}
+
+// finds whose authority the approver acts under: nil for their own, otherwise the colleague who delegated to them
+func (s *LeaveService) resolveAuthority(leave *models.LeaveRequest, approver *models.User) (*models.User, error) {
+	authErr := authorizeApprover(leave, approver)
+	if authErr == nil {
+		return nil, nil
+	}
+
+	delegators, err := s.delegationSvc.ActiveDelegators(approver.ID, models.DelegationScopeLeaveApproval)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range delegators {
+		if authorizeApprover(leave, &delegators[i]) == nil {
+			return &delegators[i], nil
+		}
+	}
+	return nil, authErr
+}
+
+// students see their own requests, approvers those of students they or their delegators cover
+func canViewLeave(leave *models.LeaveRequest, user *models.User, delegators []models.User) bool {
+	if user.ID == leave.StudentID {
+		return true
+	}
+	if leave.ApprovedBy != nil && *leave.ApprovedBy == user.ID {
+		return true
+	}
+
+	for _, viewer := range append([]models.User{*user}, delegators...) {
+		if leave.AssignedApproverID != nil && *leave.AssignedApproverID == viewer.ID {
+			return true
+		}
+		if leave.OnBehalfOfID != nil && *leave.OnBehalfOfID == viewer.ID {
+			return true
+		}
+		if viewer.Role != models.RoleStudent && viewer.Scope().Covers(&leave.Student) {
+			return true
+		}
+	}
+	return false
+}
+
+// restarts the SLA clock as the request reaches a new approver
+func (s *LeaveService) startStep(leave *models.LeaveRequest) {
+	dueAt := time.Now().Add(s.sla.For(string(leave.LeaveType)))
+	leave.DueAt = &dueAt
+	leave.Escalated = false
+	leave.EscalatedAt = nil
+}
+
+// sets the leave's length in working days per the academic calendar, counting
+// partial-day leave as its share of the day's periods and a series as the sum of its occurrences
+func (s *LeaveService) countDays(leave *models.LeaveRequest) error {
+	if !leave.IsRecurring() {
+		days, err := s.spanDays(leave, leave.StartDate, leave.EndDate)
+		if err != nil {
+			return err
+		}
+		leave.Days = days
+	} else {
+		leave.Days = 0
+		for i := range leave.Occurrences {
+			occurrence := &leave.Occurrences[i]
+			if occurrence.IsCancelled() {
+				continue
+			}
+
+			days, err := s.spanDays(leave, occurrence.StartDate, occurrence.EndDate)
+			if err != nil {
+				return err
+			}
+			occurrence.Days = days
+			leave.Days += days
+		}
+	}
+
+	if leave.Days == 0 {
+		return models.ErrNoWorkingDays
+	}
+	return nil
+}
+
+func (s *LeaveService) spanDays(leave *models.LeaveRequest, start, end time.Time) (float64, error) {
+	days, err := s.calendarSvc.WorkingDays(start, end)
+	if err != nil || days == 0 {
+		return 0, err
+	}
+	if leave.IsPartial() {
+		return float64(leave.EndPeriod-leave.StartPeriod+1) / float64(s.timetable.PeriodsPerDay), nil
+	}
+	return float64(days), nil
+}
+
+func (s *LeaveService) setSession(leave *models.LeaveRequest, req models.ApplyLeaveRequest) error {
+	return leave.SetSession(models.LeaveSession(req.Session), req.StartPeriod, req.EndPeriod, s.timetable.PeriodsPerDay)
+}
+
+// marks the student absent on each working day from the given date through the leave's end date,
+// excusing absences already recorded when the leave was filed after the fact
+func (s *LeaveService) markLeaveAttendance(leave *models.LeaveRequest, from time.Time, markerID *uint) {
+	dates, err := s.calendarSvc.WorkingDates(from, leave.EndDate)
+	if err != nil {
+		log.Printf("Failed to load calendar for leave %d: %v", leave.ID, err)
+		return
+	}
+	// A series only takes off the days of its occurrences
+	dates = slices.DeleteFunc(dates, func(date time.Time) bool { return !leave.CoversDate(date) })
+
+	if leave.Retroactive {
+		today := models.DateOnly(time.Now())
+		past := 0
+		for past < len(dates) && dates[past].Before(today) {
+			past++
+		}
+
+		if past > 0 {
+			if err := s.attendanceRepo.ExcuseAbsences(leave, dates[:past]); err != nil {
+				log.Printf("Failed to excuse absences for leave %d: %v", leave.ID, err)
+			}
+		}
+		dates = dates[past:]
+	}
+
+	// Whole-day leave is one row per date, partial-day leave one row per covered period
+	periods := []int{0}
+	if leave.IsPartial() {
+		periods = periods[:0]
+		for period := leave.StartPeriod; period <= leave.EndPeriod; period++ {
+			periods = append(periods, period)
+		}
+	}
+
+	for _, date := range dates {
+		for _, period := range periods {
+			attendance := &models.Attendance{
+				StudentID: leave.StudentID,
+				Date:      date,
+				Period:    period,
+				Present:   false,
+				MarkedBy:  markerID,
+				LeaveID:   &leave.ID,
+			}
+			s.attendanceRepo.Create(attendance)
+		}
+	}
+}
+
+// open requests whose approver has let the deadline pass
+func (s *LeaveService) GetOverdue() ([]models.LeaveRequest, error) {
+	return s.leaveRepo.FindOverdue(time.Now())
+}
+
+// flags an overdue request once, returning nil when it was decided or escalated in the meantime
+func (s *LeaveService) MarkEscalated(leaveID uint) (*models.LeaveRequest, error) {
+	var escalated *models.LeaveRequest
+	err := s.inTx(func(txSvc *LeaveService) error {
+		if err := txSvc.leaveRepo.Lock(leaveID); err != nil {
+			return models.ErrLeaveNotFound
+		}
+		leave, err := txSvc.leaveRepo.FindByID(leaveID)
+		if err != nil {
+			return models.ErrLeaveNotFound
+		}
+		if leave.Escalated || (leave.Status != models.LeaveStatusPending && leave.Status != models.LeaveStatusInReview) {
+			return nil
+		}
+
+		now := time.Now()
+		leave.Escalated = true
+		leave.EscalatedAt = &now
+		if err := txSvc.leaveRepo.Update(leave); err != nil {
+			return err
+		}
+		if err := txSvc.recordEvent(leave, leave.Status, models.LeaveEvent{Action: models.LeaveEventEscalated}); err != nil {
+			return err
+		}
+		escalated = leave
+		return nil
+	})
+	return escalated, err
+}
+
+func (s *LeaveService) GetMyLeaves(studentID uint) ([]models.LeaveRequest, error) {
+	return s.leaveRepo.FindByStudentID(studentID)
+}
+
+// returns the open requests awaiting the approver's step, including those of colleagues who delegated to them
+func (s *LeaveService) GetPendingLeaves(approverID uint) ([]models.LeaveRequest, error) {
+	approver, err := s.userRepo.FindByID(approverID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	delegators, err := s.delegationSvc.ActiveDelegators(approverID, models.DelegationScopeLeaveApproval)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := []models.ApproverScope{approver.Scope()}
+	for _, delegator := range delegators {
+		scopes = append(scopes, delegator.Scope())
+	}
+
+	leaves, err := s.leaveRepo.FindPending(scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range leaves {
+		leaves[i].SLAState = leaves[i].SLAStateAt(now)
+	}
+	return leaves, nil
+}
+
+// lists the requests matching the query that the viewer may see: students their own,
+// approvers those they or their delegators cover or have acted on, admins all
+func (s *LeaveService) Search(viewerID uint, req models.SearchLeavesRequest, page, pageSize int) ([]models.LeaveRequest, int64, error) {
+	viewer, err := s.userRepo.FindByID(viewerID)
+	if err != nil {
+		return nil, 0, models.ErrUserNotFound
+	}
+
+	search := models.LeaveSearch{
+		LeaveType:  models.LeaveType(req.LeaveType),
+		StudentID:  req.StudentID,
+		Dept:       req.Dept,
+		Hostel:     req.Hostel,
+		ApproverID: req.ApproverID,
+		Text:       strings.TrimSpace(req.Q),
+		Sort:       req.Sort,
+		Descending: req.Order != "asc",
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	if search.Sort == "" {
+		search.Sort = "created_at"
+	}
+	for _, status := range strings.Split(req.Status, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			search.Statuses = append(search.Statuses, models.LeaveStatus(status))
+		}
+	}
+	if req.From != "" {
+		if search.From, err = time.Parse("2006-01-02", req.From); err != nil {
+			return nil, 0, err
+		}
+	}
+	if req.To != "" {
+		if search.To, err = time.Parse("2006-01-02", req.To); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	switch viewer.Role {
+	case models.RoleAdmin:
+	case models.RoleStudent:
+		search.StudentID = viewer.ID
+	default:
+		delegators, err := s.delegationSvc.ActiveDelegators(viewerID, models.DelegationScopeLeaveApproval)
+		if err != nil {
+			return nil, 0, err
+		}
+		search.Scopes = []models.ApproverScope{viewer.Scope()}
+		for _, delegator := range delegators {
+			search.Scopes = append(search.Scopes, delegator.Scope())
+		}
+	}
+
+	leaves, total, err := s.leaveRepo.Search(search)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	for i := range leaves {
+		if leaves[i].Status.IsOpen() {
+			leaves[i].SLAState = leaves[i].SLAStateAt(now)
+		}
+	}
+	return leaves, total, nil
+}
+
+func (s *LeaveService) GetByID(id uint) (*models.LeaveRequest, error) {
+	return s.leaveRepo.FindByID(id)
+}
+
+// soft-deletes the request, keeping its history
+func (s *LeaveService) Delete(id, actorID uint) error {
+	return s.inTx(func(txSvc *LeaveService) error {
+		if err := txSvc.leaveRepo.Lock(id); err != nil {
+			return models.ErrLeaveNotFound
+		}
+		return txSvc.delete(id, actorID)
+	})
+}
+
+// removes the request and undoes what its approval granted; the caller holds the request's row
+func (s *LeaveService) delete(id, actorID uint) error {
+	leave, err := s.leaveRepo.FindByID(id)
+	if err != nil {
+		return models.ErrLeaveNotFound
+	}
+
+	// Open extensions could never be approved against a deleted parent
+	if err := s.closeExtensions(leave, actorID); err != nil {
+		return err
+	}
+
+	if leave.Status == models.LeaveStatusApproved {
+		if err := s.balanceSvc.LockStudent(leave.StudentID); err != nil {
+			return err
+		}
+		if err := s.balanceSvc.Credit(leave); err != nil {
+			return err
+		}
+		if err := s.attendanceRepo.ReleaseByLeave(leave.ID); err != nil {
+			return err
+		}
+		if err := s.gatePassSvc.Revoke(leave.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.leaveRepo.Delete(id); err != nil {
+		return err
+	}
+
+	return s.recordEvent(leave, leave.Status, models.LeaveEvent{Action: models.LeaveEventDeleted, ActorID: &actorID})
+}
+
+// the guardian's answer on a request the viewer can see
+func (s *LeaveService) GetConsent(leaveID, viewerID uint) (*models.GuardianConsent, error) {
+	if _, _, err := s.ViewLeave(leaveID, viewerID); err != nil {
+		return nil, err
+	}
+	return s.consentSvc.GetForLeave(leaveID)
+}
+
+// the request's recorded events, oldest first; admins can still read the history of a deleted request
+func (s *LeaveService) History(leaveID, viewerID uint) ([]models.LeaveEvent, error) {
+	_, _, viewErr := s.ViewLeave(leaveID, viewerID)
+	if viewErr != nil && !errors.Is(viewErr, models.ErrLeaveNotFound) {
+		return nil, viewErr
+	}
+
+	if viewErr != nil {
+		viewer, err := s.userRepo.FindByID(viewerID)
+		if err != nil || viewer.Role != models.RoleAdmin {
+			return nil, viewErr
+		}
+	}
+
+	events, err := s.eventRepo.FindByLeaveID(leaveID)
+	if err != nil {
+		return nil, err
+	}
+	if viewErr != nil && len(events) == 0 {
+		return nil, viewErr
+	}
+	return events, nil
+}
+
+// appends to the request's history; from is its status before the change
+func (s *LeaveService) recordEvent(leave *models.LeaveRequest, from models.LeaveStatus, event models.LeaveEvent) error {
+	event.LeaveID = leave.ID
+	event.FromStatus = from
+	event.ToStatus = leave.Status
+	event.Step = leave.CurrentStep
+	return s.eventRepo.Create(&event)
+}
+
+func (s *LeaveService) GetLeaveStats(startDate, endDate time.Time) ([]models.LeaveTypeStat, error) {
+	return s.leaveRepo.GetLeaveStats(startDate, endDate)
+}
+
+// filed for dates that have already begun
+func isRetroactive(leave *models.LeaveRequest) bool {
+	return leave.StartDate.Before(models.DateOnly(time.Now()))
+}
+
+func parseLeaveDates(req models.ApplyLeaveRequest) (time.Time, time.Time, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return startDate, endDate, nil
+}
+
+// expands the request's dates into a series when a recurrence is given, or makes it a single leave again
+func setRecurrence(leave *models.LeaveRequest, input *models.RecurrenceInput) error {
+	if input == nil {
+		leave.Recurrence = ""
+		leave.RecurrenceInterval = 0
+		leave.RecurrenceUntil = nil
+		leave.Occurrences = nil
+		return nil
+	}
+
+	// A series is a standing commitment planned ahead, never filed after the fact
+	if leave.Retroactive {
+		return models.ErrInvalidRecurrence
+	}
+
+	until, err := time.Parse("2006-01-02", input.Until)
+	if err != nil {
+		return err
+	}
+
+	interval := max(input.Interval, 1)
+	frequency := models.RecurrenceFrequency(input.Frequency)
+	occurrences, err := models.ExpandRecurrence(leave.StartDate, leave.EndDate, frequency, interval, until)
+	if err != nil {
+		return err
+	}
+
+	leave.Recurrence = frequency
+	leave.RecurrenceInterval = interval
+	leave.RecurrenceUntil = &until
+	leave.Occurrences = occurrences
+	leave.EndDate = occurrences[len(occurrences)-1].EndDate
+	return nil
 }
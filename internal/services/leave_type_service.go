@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+)
+
+type LeaveTypeService struct {
+	repo        *repositories.LeaveTypeRepository
+	workflowSvc *WorkflowService
+}
+
+func NewLeaveTypeService(repo *repositories.LeaveTypeRepository, workflowSvc *WorkflowService) *LeaveTypeService {
+	return &LeaveTypeService{
+		repo:        repo,
+		workflowSvc: workflowSvc,
+	}
+}
+
+// admins also see deactivated types
+func (s *LeaveTypeService) List(includeInactive bool) ([]models.LeaveTypeDefinition, error) {
+	return s.repo.FindAll(!includeInactive)
+}
+
+func (s *LeaveTypeService) Get(code models.LeaveType) (*models.LeaveTypeDefinition, error) {
+	leaveType, err := s.repo.FindByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if leaveType == nil {
+		return nil, models.ErrLeaveTypeNotFound
+	}
+
+	workflow, err := s.workflowSvc.GetWorkflow(code)
+	if err != nil {
+		return nil, err
+	}
+	leaveType.Workflow = workflow
+	return leaveType, nil
+}
+
+// fails unless the code names an active catalogue entry
+func (s *LeaveTypeService) Require(code models.LeaveType) (*models.LeaveTypeDefinition, error) {
+	leaveType, err := s.repo.FindByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if leaveType == nil {
+		return nil, models.ErrLeaveTypeNotFound
+	}
+	if !leaveType.Active {
+		return nil, models.ErrLeaveTypeInactive
+	}
+	return leaveType, nil
+}
+
+func (s *LeaveTypeService) Create(req models.CreateLeaveTypeRequest) (*models.LeaveTypeDefinition, error) {
+	existing, err := s.repo.FindByCode(models.LeaveType(req.Code))
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, models.ErrLeaveTypeExists
+	}
+
+	leaveType := &models.LeaveTypeDefinition{Code: models.LeaveType(req.Code), Active: true}
+	applyLeaveTypeRequest(leaveType, req.UpdateLeaveTypeRequest)
+
+	if err := s.repo.Create(leaveType); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveSteps(leaveType, req.Steps); err != nil {
+		return nil, err
+	}
+	return leaveType, nil
+}
+
+func (s *LeaveTypeService) Update(code models.LeaveType, req models.UpdateLeaveTypeRequest) (*models.LeaveTypeDefinition, error) {
+	leaveType, err := s.repo.FindByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if leaveType == nil {
+		return nil, models.ErrLeaveTypeNotFound
+	}
+
+	applyLeaveTypeRequest(leaveType, req)
+
+	if err := s.repo.Update(leaveType); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveSteps(leaveType, req.Steps); err != nil {
+		return nil, err
+	}
+	return leaveType, nil
+}
+
+// only unused types can be removed so past requests keep a catalogue entry
+func (s *LeaveTypeService) Delete(code models.LeaveType) error {
+	leaveType, err := s.repo.FindByCode(code)
+	if err != nil {
+		return err
+	}
+	if leaveType == nil {
+		return models.ErrLeaveTypeNotFound
+	}
+
+	inUse, err := s.repo.InUse(code)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return models.ErrLeaveTypeInUse
+	}
+
+	if err := s.workflowSvc.DeleteWorkflow(code); err != nil && !errors.Is(err, models.ErrWorkflowNotFound) {
+		return err
+	}
+	return s.repo.Delete(leaveType.ID)
+}
+
+// replaces the type's approval chain when steps are given
+func (s *LeaveTypeService) saveSteps(leaveType *models.LeaveTypeDefinition, steps []models.ApprovalStepInput) error {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	workflow, err := s.workflowSvc.SaveWorkflow(leaveType.Code, models.SaveWorkflowRequest{Steps: steps})
+	if err != nil {
+		return err
+	}
+	leaveType.Workflow = workflow
+	return nil
+}
+
+func applyLeaveTypeRequest(leaveType *models.LeaveTypeDefinition, req models.UpdateLeaveTypeRequest) {
+	leaveType.Name = req.Name
+	leaveType.Description = req.Description
+	leaveType.DefaultQuota = req.DefaultQuota
+	leaveType.RequiresAttachment = req.RequiresAttachment
+	if req.Active != nil {
+		leaveType.Active = *req.Active
+	}
+
+	leaveType.DefaultQuotaPeriod = models.QuotaPeriodAnnual
+	if req.DefaultQuotaPeriod != "" {
+		leaveType.DefaultQuotaPeriod = models.QuotaPeriod(req.DefaultQuotaPeriod)
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+)
+
+// LoginThrottleService locks sign-in for an account email or client address after repeated failures,
+// doubling the lockout with every further failure
+type LoginThrottleService struct {
+	repo            *repositories.LoginThrottleRepository
+	notificationSvc *NotificationService
+	cfg             core.LoginConfig
+}
+
+func NewLoginThrottleService(
+	repo *repositories.LoginThrottleRepository,
+	notificationSvc *NotificationService,
+	cfg core.LoginConfig,
+) *LoginThrottleService {
+	return &LoginThrottleService{
+		repo:            repo,
+		notificationSvc: notificationSvc,
+		cfg:             cfg,
+	}
+}
+
+// refuses the attempt while either the account or the address is locked
+func (s *LoginThrottleService) Check(email, ip string) error {
+	throttles, err := s.repo.FindByKeys([]string{accountKey(email), ipKey(ip)})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var wait time.Duration
+	for _, throttle := range throttles {
+		wait = max(wait, throttle.LockedFor(now))
+	}
+	if wait > 0 {
+		return &models.LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// counts a failed attempt; user is nil when the email matches no account, which is throttled all the same
+func (s *LoginThrottleService) Fail(email, ip string, user *models.User) error {
+	now := time.Now()
+	windowStart := now.Add(-s.cfg.Window)
+
+	account, err := s.repo.RecordFailure(accountKey(email), now, windowStart)
+	if err != nil {
+		return err
+	}
+	if lockout := s.lockout(account.Failures, s.cfg.MaxAttempts); lockout > 0 {
+		until := now.Add(lockout)
+		if err := s.repo.Lock(account.Key, until); err != nil {
+			return err
+		}
+		// tell the owner once per run of failures rather than on every extension
+		if user != nil && account.Failures == s.cfg.MaxAttempts {
+			s.notificationSvc.SendAccountLocked(user, until)
+		}
+	}
+
+	if ip == "" {
+		return nil
+	}
+	address, err := s.repo.RecordFailure(ipKey(ip), now, windowStart)
+	if err != nil {
+		return err
+	}
+	if lockout := s.lockout(address.Failures, s.cfg.IPMaxAttempts); lockout > 0 {
+		return s.repo.Lock(address.Key, now.Add(lockout))
+	}
+	return nil
+}
+
+// clears the account's failures after a correct password; the address keeps its count
+func (s *LoginThrottleService) Succeed(email string) error {
+	return s.repo.Delete(accountKey(email))
+}
+
+// lifts an account lockout early
+func (s *LoginThrottleService) Unlock(email string) error {
+	return s.repo.Delete(accountKey(email))
+}
+
+// the lockout earned by the failure count, zero below the limit
+func (s *LoginThrottleService) lockout(failures, limit int) time.Duration {
+	if limit <= 0 || failures < limit {
+		return 0
+	}
+
+	lockout := s.cfg.BaseLockout
+	for i := limit; i < failures && lockout < s.cfg.MaxLockout; i++ {
+		lockout *= 2
+	}
+	return min(lockout, s.cfg.MaxLockout)
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/core"
+)
+
+func TestLoginThrottleServiceLockout(t *testing.T) {
+	svc := &LoginThrottleService{cfg: core.LoginConfig{
+		BaseLockout: time.Minute,
+		MaxLockout:  time.Hour,
+	}}
+
+	tests := []struct {
+		name     string
+		failures int
+		limit    int
+		want     time.Duration
+	}{
+		{"below the limit", 4, 5, 0},
+		{"at the limit", 5, 5, time.Minute},
+		{"one past the limit doubles", 6, 5, 2 * time.Minute},
+		{"two past the limit doubles again", 7, 5, 4 * time.Minute},
+		{"last step under the cap", 10, 5, 32 * time.Minute},
+		{"capped", 11, 5, time.Hour},
+		{"stays capped", 500, 5, time.Hour},
+		{"no limit configured", 50, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := svc.lockout(tt.failures, tt.limit); got != tt.want {
+				t.Errorf("lockout(%d, %d) = %v, want %v", tt.failures, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/auth"
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+)
+
+// the second-factor storage MFAService relies on, as provided by repositories.MFARepository
+type mfaStore interface {
+	ReplaceRecoveryCodes(userID uint, codes []models.RecoveryCode) error
+	UseRecoveryCode(userID uint, hash string, now time.Time) (bool, error)
+	AdvanceStep(userID uint, step int64) (bool, error)
+	SetSecret(userID uint, sealed string) error
+	Enable(userID uint, now time.Time) error
+	Disable(userID uint) error
+}
+
+// MFAService enrols accounts in TOTP two-factor authentication and checks the second step of a login
+type MFAService struct {
+	repo      mfaStore
+	tokenRepo *repositories.TokenRepository
+	userRepo  *repositories.UserRepository
+	tokenSvc  *TokenService
+	cipher    *auth.Cipher
+	cfg       core.MFAConfig
+}
+
+func NewMFAService(
+	repo *repositories.MFARepository,
+	tokenRepo *repositories.TokenRepository,
+	userRepo *repositories.UserRepository,
+	tokenSvc *TokenService,
+	cipher *auth.Cipher,
+	cfg core.MFAConfig,
+) *MFAService {
+	return &MFAService{
+		repo:      repo,
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+		tokenSvc:  tokenSvc,
+		cipher:    cipher,
+		cfg:       cfg,
+	}
+}
+
+// whether accounts with the role must sign in with a second factor
+func (s *MFAService) Required(role models.Role) bool {
+	return slices.Contains(s.cfg.RequiredRoles, string(role))
+}
+
+// generates a new secret for the user to add to their authenticator; it takes effect once Enable confirms a code
+func (s *MFAService) Setup(userID uint) (*models.MFASetup, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+	if user.IsMFAEnabled() {
+		return nil, models.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.cipher.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetSecret(user.ID, sealed); err != nil {
+		return nil, err
+	}
+
+	return &models.MFASetup{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(s.cfg.Issuer, user.Email, secret),
+	}, nil
+}
+
+// turns two-factor on after a first valid code and starts a session that counts as two-factor
+func (s *MFAService) Enable(userID uint, code string) (*models.MFAEnabled, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+	if user.IsMFAEnabled() {
+		return nil, models.ErrMFAAlreadyEnabled
+	}
+	if user.MFASecret == "" {
+		return nil, models.ErrMFANotEnrolled
+	}
+
+	if err := s.checkTOTP(user, code); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Enable(user.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.newRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.tokenSvc.Issue(user, true)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MFAEnabled{TokenPair: tokens, RecoveryCodes: codes}, nil
+}
+
+// turns two-factor off, confirmed with a current or recovery code; roles that require it cannot
+func (s *MFAService) Disable(userID uint, code string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return models.ErrUserNotFound
+	}
+	if !user.IsMFAEnabled() {
+		return models.ErrMFANotEnrolled
+	}
+	if s.Required(user.Role) {
+		return models.ErrMFAEnforced
+	}
+
+	if err := s.checkCode(user, code); err != nil {
+		return err
+	}
+	return s.repo.Disable(user.ID)
+}
+
+// replaces the recovery codes, confirmed with a code from the authenticator
+func (s *MFAService) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+	if !user.IsMFAEnabled() {
+		return nil, models.ErrMFANotEnrolled
+	}
+
+	if err := s.checkTOTP(user, code); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(user.ID)
+}
+
+// lets an admin clear two-factor for someone who lost both their authenticator and recovery codes
+func (s *MFAService) Reset(userID uint) error {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return models.ErrUserNotFound
+	}
+	if err := s.repo.Disable(userID); err != nil {
+		return err
+	}
+	return s.tokenSvc.RevokeUser(userID)
+}
+
+// issues the single-use token that stands in for the password step until a code is given
+func (s *MFAService) Challenge(user *models.User) (*models.MFAChallenge, error) {
+	token, _, err := issueAccountToken(s.tokenRepo, user.ID, models.AccountTokenMFAChallenge, s.cfg.ChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &models.MFAChallenge{
+		ChallengeToken: token,
+		ExpiresIn:      int64(s.cfg.ChallengeTTL.Seconds()),
+	}, nil
+}
+
+// completes a login; the challenge is used up either way, so a wrong code means signing in again
+func (s *MFAService) Verify(challengeToken, code string) (*models.User, *models.TokenPair, error) {
+	user, err := redeemAccountToken(s.tokenRepo, s.userRepo, challengeToken, models.AccountTokenMFAChallenge)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.IsMFAEnabled() {
+		return nil, nil, models.ErrInvalidAccountToken
+	}
+
+	if err := s.checkCode(user, code); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.tokenSvc.Issue(user, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// accepts a TOTP code or, failing that, an unused recovery code
+func (s *MFAService) checkCode(user *models.User, code string) error {
+	if err := s.checkTOTP(user, code); err == nil {
+		return nil
+	}
+
+	used, err := s.repo.UseRecoveryCode(user.ID, hashToken(normalizeRecoveryCode(code)), time.Now())
+	if err != nil {
+		return err
+	}
+	if !used {
+		return models.ErrInvalidMFACode
+	}
+	return nil
+}
+
+// accepts each time step once, so an observed code cannot be replayed
+func (s *MFAService) checkTOTP(user *models.User, code string) error {
+	secret, err := s.cipher.Open(user.MFASecret)
+	if err != nil {
+		return err
+	}
+
+	step, ok := auth.VerifyTOTP(secret, code, time.Now())
+	if !ok {
+		return models.ErrInvalidMFACode
+	}
+
+	advanced, err := s.repo.AdvanceStep(user.ID, step)
+	if err != nil {
+		return err
+	}
+	if !advanced {
+		return models.ErrInvalidMFACode
+	}
+	return nil
+}
+
+// returns the codes for the user to write down; only their hashes are stored
+func (s *MFAService) newRecoveryCodes(userID uint) ([]string, error) {
+	codes := make([]string, models.RecoveryCodeCount)
+	stored := make([]models.RecoveryCode, models.RecoveryCodeCount)
+
+	for i := range codes {
+		raw := make([]byte, 10)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		code := base32.StdEncoding.EncodeToString(raw)
+		codes[i] = code[:8] + "-" + code[8:]
+		stored[i] = models.RecoveryCode{UserID: userID, CodeHash: hashToken(code)}
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(userID, stored); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/auth"
+	"github.com/prannvs/campus-leave-system/internal/models"
+)
+
+// keeps the last TOTP step and the recovery codes in memory with the repository's conditional updates
+type memoryMFAStore struct {
+	lastStep int64
+	codes    []models.RecoveryCode
+}
+
+func (m *memoryMFAStore) ReplaceRecoveryCodes(userID uint, codes []models.RecoveryCode) error {
+	m.codes = codes
+	return nil
+}
+
+func (m *memoryMFAStore) UseRecoveryCode(userID uint, hash string, now time.Time) (bool, error) {
+	for i := range m.codes {
+		if m.codes[i].UserID == userID && m.codes[i].CodeHash == hash && m.codes[i].UsedAt == nil {
+			m.codes[i].UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryMFAStore) AdvanceStep(userID uint, step int64) (bool, error) {
+	if m.lastStep >= step {
+		return false, nil
+	}
+	m.lastStep = step
+	return true, nil
+}
+
+func (m *memoryMFAStore) SetSecret(userID uint, sealed string) error { return nil }
+
+func (m *memoryMFAStore) Enable(userID uint, now time.Time) error { return nil }
+
+func (m *memoryMFAStore) Disable(userID uint) error { return nil }
+
+func newTestMFAService(t *testing.T) (*MFAService, *memoryMFAStore, *models.User, string) {
+	t.Helper()
+	cipher := auth.NewCipher("test-secret", "mfa")
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret() error = %v", err)
+	}
+	sealed, err := cipher.Seal(secret)
+	if err != nil {
+		t.Fatalf("Seal() error = %v", err)
+	}
+
+	store := &memoryMFAStore{}
+	user := &models.User{ID: 1, MFASecret: sealed}
+	return &MFAService{repo: store, cipher: cipher}, store, user, secret
+}
+
+func totpAt(t *testing.T, secret string, step int64) string {
+	t.Helper()
+	code, err := auth.TOTPCode(secret, step)
+	if err != nil {
+		t.Fatalf("TOTPCode() error = %v", err)
+	}
+	return code
+}
+
+func TestMFAServiceCheckTOTPRejectsReplay(t *testing.T) {
+	tests := []struct {
+		name string
+		// steps relative to now, presented in order
+		steps   []int64
+		wantErr []error
+	}{
+		{
+			name:    "fresh code",
+			steps:   []int64{0},
+			wantErr: []error{nil},
+		},
+		{
+			name:    "same code twice",
+			steps:   []int64{0, 0},
+			wantErr: []error{nil, models.ErrInvalidMFACode},
+		},
+		{
+			name:    "older code after a newer one",
+			steps:   []int64{0, -1},
+			wantErr: []error{nil, models.ErrInvalidMFACode},
+		},
+		{
+			name:    "newer code after an older one",
+			steps:   []int64{-1, 0},
+			wantErr: []error{nil, nil},
+		},
+		{
+			name:    "code outside the skew window",
+			steps:   []int64{-3},
+			wantErr: []error{models.ErrInvalidMFACode},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, user, secret := newTestMFAService(t)
+			now := auth.TOTPStep(time.Now())
+			for i, offset := range tt.steps {
+				err := svc.checkTOTP(user, totpAt(t, secret, now+offset))
+				if !errors.Is(err, tt.wantErr[i]) {
+					t.Fatalf("code %d: checkTOTP() error = %v, want %v", i, err, tt.wantErr[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMFAServiceRecoveryCodes(t *testing.T) {
+	svc, store, user, _ := newTestMFAService(t)
+	codes, err := svc.newRecoveryCodes(user.ID)
+	if err != nil {
+		t.Fatalf("newRecoveryCodes() error = %v", err)
+	}
+
+	if len(codes) != models.RecoveryCodeCount || len(store.codes) != models.RecoveryCodeCount {
+		t.Fatalf("got %d codes and %d stored, want %d", len(codes), len(store.codes), models.RecoveryCodeCount)
+	}
+	format := regexp.MustCompile(`^[A-Z2-7]{8}-[A-Z2-7]{8}$`)
+	for i, code := range codes {
+		if !format.MatchString(code) {
+			t.Errorf("code %q does not match XXXXXXXX-XXXXXXXX", code)
+		}
+		if store.codes[i].CodeHash == code || store.codes[i].CodeHash != hashToken(normalizeRecoveryCode(code)) {
+			t.Errorf("code %d is not stored as the hash of its normalised form", i)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{"as shown", codes[0], nil},
+		{"already used", codes[0], models.ErrInvalidMFACode},
+		{"lower case without the dash", strings.ToLower(strings.ReplaceAll(codes[1], "-", "")), nil},
+		{"spaced", strings.ReplaceAll(codes[2], "-", " "), nil},
+		{"unknown", "AAAAAAAA-AAAAAAAA", models.ErrInvalidMFACode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.checkCode(user, tt.code); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkCode(%q) error = %v, want %v", tt.code, err, tt.wantErr)
+			}
+		})
+	}
+}
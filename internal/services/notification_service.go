@@ -37,6 +37,157 @@ func (s *NotificationService) SendLeaveStatusNotification(leave *models.LeaveReq
 	}()
 }
 
+func (s *NotificationService) SendEscalationNotification(leave *models.LeaveRequest, recipients []models.User) {
+	go func() {
+		subject := fmt.Sprintf("Escalated: %s leave request #%d", leave.LeaveType, leave.ID)
+		body := fmt.Sprintf(
+			"The %s leave request from %s (%s to %s) has been waiting on the %s step past its deadline and needs attention.",
+			leave.LeaveType,
+			leave.Student.Name,
+			leave.StartDate.Format("2006-01-02"),
+			leave.EndDate.Format("2006-01-02"),
+			leave.AwaitingRole,
+		)
+
+		for _, recipient := range recipients {
+			if err := s.sendEmail(recipient.Email, subject, body); err != nil {
+				log.Printf("Failed to send escalation email to %s: %v", recipient.Email, err)
+				continue
+			}
+			log.Printf("Escalation email sent to %s for leave %d", recipient.Email, leave.ID)
+		}
+	}()
+}
+
+func (s *NotificationService) SendCommentNotification(leave *models.LeaveRequest, comment *models.LeaveComment, recipients []models.User) {
+	go func() {
+		subject := fmt.Sprintf("New comment on %s leave request #%d", leave.LeaveType, leave.ID)
+		if comment.RequestedInfo {
+			subject = fmt.Sprintf("More information needed on %s leave request #%d", leave.LeaveType, leave.ID)
+		}
+		body := fmt.Sprintf("%s wrote:\n\n%s", comment.Author.Name, comment.Body)
+
+		for _, recipient := range recipients {
+			if err := s.sendEmail(recipient.Email, subject, body); err != nil {
+				log.Printf("Failed to send comment email to %s: %v", recipient.Email, err)
+				continue
+			}
+			log.Printf("Comment email sent to %s for leave %d", recipient.Email, leave.ID)
+		}
+	}()
+}
+
+func (s *NotificationService) SendConsentRequest(consent *models.GuardianConsent, leave *models.LeaveRequest, student *models.User, link string) {
+	go func() {
+		subject := fmt.Sprintf("Consent needed for %s's leave", student.Name)
+		body := fmt.Sprintf(
+			"%s has requested %s leave from %s to %s.\n\nReason: %s\n\nPlease approve or decline using this link, which can be used once and expires on %s:\n%s",
+			student.Name,
+			leave.LeaveType,
+			leave.StartDate.Format("2006-01-02"),
+			leave.EndDate.Format("2006-01-02"),
+			leave.Reason,
+			consent.ExpiresAt.Format("2006-01-02 15:04"),
+			link,
+		)
+
+		if err := s.sendEmail(consent.GuardianEmail, subject, body); err != nil {
+			log.Printf("Failed to send consent request for leave %d: %v", leave.ID, err)
+			return
+		}
+		log.Printf("Consent request sent to %s for leave %d", consent.GuardianEmail, leave.ID)
+	}()
+}
+
+func (s *NotificationService) SendConsentResponseNotification(leave *models.LeaveRequest, consent *models.GuardianConsent) {
+	go func() {
+		subject := fmt.Sprintf("Guardian consent %s", consent.Status)
+		body := fmt.Sprintf(
+			"Your guardian has %s consent for your leave from %s to %s.",
+			consent.Status,
+			leave.StartDate.Format("2006-01-02"),
+			leave.EndDate.Format("2006-01-02"),
+		)
+
+		if err := s.sendEmail(leave.Student.Email, subject, body); err != nil {
+			log.Printf("Failed to send consent response email: %v", err)
+			return
+		}
+		log.Printf("Consent response email sent to %s for leave %d", leave.Student.Email, leave.ID)
+	}()
+}
+
+func (s *NotificationService) SendInvitation(invitation *models.Invitation, link string) {
+	go func() {
+		subject := "You're invited to the campus leave system"
+		body := fmt.Sprintf(
+			"You have been invited to join as %s. Register with this email address using the link below before %s:\n%s",
+			invitation.Role,
+			invitation.ExpiresAt.Format("2006-01-02 15:04"),
+			link,
+		)
+
+		if err := s.sendEmail(invitation.Email, subject, body); err != nil {
+			log.Printf("Failed to send invitation to %s: %v", invitation.Email, err)
+			return
+		}
+		log.Printf("Invitation sent to %s", invitation.Email)
+	}()
+}
+
+func (s *NotificationService) SendEmailVerification(user *models.User, link string, expiresAt time.Time) {
+	go func() {
+		subject := "Confirm your email address"
+		body := fmt.Sprintf(
+			"Hi %s,\n\nConfirm this is your email address before %s so you can sign in:\n%s",
+			user.Name,
+			expiresAt.Format("2006-01-02 15:04"),
+			link,
+		)
+
+		if err := s.sendEmail(user.Email, subject, body); err != nil {
+			log.Printf("Failed to send verification email to %s: %v", user.Email, err)
+			return
+		}
+		log.Printf("Verification email sent to %s", user.Email)
+	}()
+}
+
+func (s *NotificationService) SendPasswordReset(user *models.User, link string, expiresAt time.Time) {
+	go func() {
+		subject := "Reset your password"
+		body := fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to reset the password for this account. Choose a new one before %s using the link below; if it was not you, ignore this email:\n%s",
+			user.Name,
+			expiresAt.Format("2006-01-02 15:04"),
+			link,
+		)
+
+		if err := s.sendEmail(user.Email, subject, body); err != nil {
+			log.Printf("Failed to send password reset to %s: %v", user.Email, err)
+			return
+		}
+		log.Printf("Password reset sent to %s", user.Email)
+	}()
+}
+
+func (s *NotificationService) SendAccountLocked(user *models.User, until time.Time) {
+	go func() {
+		subject := "Sign-in temporarily locked"
+		body := fmt.Sprintf(
+			"Hi %s,\n\nThere were several failed attempts to sign in to your account, so sign-in is locked until %s. If this was not you, consider resetting your password.",
+			user.Name,
+			until.Format("2006-01-02 15:04"),
+		)
+
+		if err := s.sendEmail(user.Email, subject, body); err != nil {
+			log.Printf("Failed to send lockout notice to %s: %v", user.Email, err)
+			return
+		}
+		log.Printf("Lockout notice sent to %s", user.Email)
+	}()
+}
+
 func (s *NotificationService) ScheduleLeaveReminder(leave *models.LeaveRequest) {
 	reminderTime := leave.StartDate.Add(-24 * time.Hour)
 	delay := time.Until(reminderTime)
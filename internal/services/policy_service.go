@@ -0,0 +1,239 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/core"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+	"go.yaml.in/yaml/v3"
+)
+
+type PolicyService struct {
+	repo          *repositories.PolicyRepository
+	leaveTypeRepo *repositories.LeaveTypeRepository
+	calendarSvc   *CalendarService
+	cfg           core.PolicyConfig
+	// attachment thresholds for leave types without a stored policy
+	attachmentDefaults map[string]int
+}
+
+func NewPolicyService(
+	repo *repositories.PolicyRepository,
+	leaveTypeRepo *repositories.LeaveTypeRepository,
+	calendarSvc *CalendarService,
+	cfg core.PolicyConfig,
+	attachmentDefaults map[string]int,
+) *PolicyService {
+	return &PolicyService{
+		repo:               repo,
+		leaveTypeRepo:      leaveTypeRepo,
+		calendarSvc:        calendarSvc,
+		cfg:                cfg,
+		attachmentDefaults: attachmentDefaults,
+	}
+}
+
+// returns the leave type's policy, or a permissive default when none is configured
+func (s *PolicyService) For(leaveType models.LeaveType) (*models.LeavePolicy, error) {
+	policy, err := s.repo.FindByLeaveType(leaveType)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy == nil {
+		policy = &models.LeavePolicy{
+			LeaveType:        leaveType,
+			AllowRetroactive: leaveType.AllowsRetroactive(),
+		}
+		if limit, ok := s.attachmentDefaults[string(leaveType)]; ok {
+			policy.AttachmentOverDays = &limit
+		}
+	}
+
+	// Catalogue entries can demand a document whatever the length
+	definition, err := s.leaveTypeRepo.FindByCode(leaveType)
+	if err != nil {
+		return nil, err
+	}
+	if definition != nil && definition.RequiresAttachment {
+		always := 0
+		policy.AttachmentOverDays = &always
+	}
+	return policy, nil
+}
+
+// checks the request against every rule of its type's policy and reports all that fail;
+// each occurrence of a series is checked as a leave of its own
+func (s *PolicyService) Evaluate(leave *models.LeaveRequest, applicant *models.User) error {
+	policy, err := s.For(leave.LeaveType)
+	if err != nil {
+		return err
+	}
+
+	if !leave.IsRecurring() {
+		return s.evaluate(policy, leave, applicant)
+	}
+
+	var violations []models.PolicyViolation
+	for _, occurrence := range leave.ActiveOccurrences() {
+		instance := *leave
+		instance.StartDate, instance.EndDate = occurrence.StartDate, occurrence.EndDate
+		instance.Recurrence, instance.Occurrences = "", nil
+
+		err := s.evaluate(policy, &instance, applicant)
+		var policyErr *models.PolicyViolationError
+		if !errors.As(err, &policyErr) {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		for _, violation := range policyErr.Violations {
+			if !slices.Contains(violations, violation) {
+				violations = append(violations, violation)
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &models.PolicyViolationError{Violations: violations}
+	}
+	return nil
+}
+
+func (s *PolicyService) evaluate(policy *models.LeavePolicy, leave *models.LeaveRequest, applicant *models.User) error {
+	var violations []models.PolicyViolation
+	add := func(rule, format string, args ...interface{}) {
+		violations = append(violations, models.PolicyViolation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	today := models.DateOnly(time.Now())
+	start := models.DateOnly(leave.StartDate)
+	end := models.DateOnly(leave.EndDate)
+
+	if start.Before(today) {
+		window := policy.RetroactiveDays
+		if window == 0 {
+			window = s.cfg.RetroactiveWindowDays
+		}
+
+		switch {
+		case !policy.AllowRetroactive || !leave.LeaveType.AllowsRetroactive():
+			add(models.RuleRetroactive, "%s", models.ErrPastDate.Error())
+		case daysBetween(start, today) > window:
+			add(models.RuleRetroactive, "%s leave can only be filed up to %d day(s) after it starts",
+				leave.LeaveType, window)
+		}
+	} else if notice := daysBetween(today, start); notice < policy.MinNoticeDays {
+		add(models.RuleMinNotice, "%s leave must be requested at least %d day(s) in advance",
+			leave.LeaveType, policy.MinNoticeDays)
+	}
+
+	if policy.MaxConsecutiveDays > 0 && daysBetween(start, end)+1 > policy.MaxConsecutiveDays {
+		add(models.RuleMaxConsecutive, "%s leave cannot run longer than %d consecutive day(s)",
+			leave.LeaveType, policy.MaxConsecutiveDays)
+	}
+
+	if !policy.AllowsRole(applicant.Role) {
+		add(models.RuleAllowedRoles, "%s leave is not available to %s users", leave.LeaveType, applicant.Role)
+	}
+
+	for _, blackout := range policy.Blackouts {
+		if blackout.Overlaps(start, end) {
+			add(models.RuleBlackout, "%s leave is not allowed during %s (%s to %s)", leave.LeaveType, blackout.Name,
+				blackout.StartDate.Format("2006-01-02"), blackout.EndDate.Format("2006-01-02"))
+		}
+	}
+
+	if policy.BlockExamWeeks {
+		calendar, err := s.calendarSvc.Load(start, end)
+		if err != nil {
+			return err
+		}
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			if calendar.InExamWeek(day) {
+				add(models.RuleExamWeek, "%s leave is not allowed during exam weeks", leave.LeaveType)
+				break
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &models.PolicyViolationError{Violations: violations}
+	}
+	return nil
+}
+
+func (s *PolicyService) GetPolicies() ([]models.LeavePolicy, error) {
+	return s.repo.FindAll()
+}
+
+func (s *PolicyService) SavePolicy(leaveType models.LeaveType, req models.SavePolicyRequest) (*models.LeavePolicy, error) {
+	if req.AllowRetroactive && !leaveType.AllowsRetroactive() {
+		return nil, models.ErrRetroactiveNotAllowed
+	}
+
+	policy := &models.LeavePolicy{
+		LeaveType:          leaveType,
+		MinNoticeDays:      req.MinNoticeDays,
+		MaxConsecutiveDays: req.MaxConsecutiveDays,
+		BlockExamWeeks:     req.BlockExamWeeks,
+		AttachmentOverDays: req.AttachmentOverDays,
+		AllowRetroactive:   req.AllowRetroactive,
+		RetroactiveDays:    req.RetroactiveDays,
+	}
+
+	for _, role := range req.AllowedRoles {
+		policy.AllowedRoles = append(policy.AllowedRoles, models.Role(role))
+	}
+
+	for _, input := range req.Blackouts {
+		startDate, endDate, err := parseDateRange(input.StartDate, input.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		policy.Blackouts = append(policy.Blackouts, models.PolicyBlackout{
+			Name:      input.Name,
+			StartDate: startDate,
+			EndDate:   endDate,
+		})
+	}
+
+	if err := s.repo.Save(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (s *PolicyService) DeletePolicy(leaveType models.LeaveType) error {
+	return s.repo.Delete(leaveType)
+}
+
+// stores every policy declared in the YAML file, replacing the existing rules for those types
+func (s *PolicyService) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var file models.PolicyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	for leaveType, req := range file.Policies {
+		if _, err := s.SavePolicy(models.LeaveType(leaveType), req); err != nil {
+			return fmt.Errorf("policy for %s: %w", leaveType, err)
+		}
+	}
+	return nil
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/auth"
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+)
+
+// the token storage TokenService relies on, as provided by repositories.TokenRepository
+type refreshTokenStore interface {
+	CreateRefresh(token *models.RefreshToken) error
+	FindRefreshByHash(hash string) (*models.RefreshToken, error)
+	ConsumeRefresh(id uint, now time.Time) (bool, error)
+	RevokeFamily(familyID string, now time.Time) error
+	RevokeAllForUser(userID uint, now time.Time) error
+	Deny(token *models.RevokedToken) error
+	IsDenied(jti string) (bool, error)
+	PurgeExpired(now time.Time) error
+}
+
+// looks accounts up by id, as repositories.UserRepository does
+type userFinder interface {
+	FindByID(id uint) (*models.User, error)
+}
+
+// TokenService issues short-lived access tokens alongside rotating refresh tokens and revokes them
+type TokenService struct {
+	repo          refreshTokenStore
+	userRepo      userFinder
+	jwtService    *auth.JWTService
+	refreshExpiry time.Duration
+}
+
+func NewTokenService(
+	repo *repositories.TokenRepository,
+	userRepo *repositories.UserRepository,
+	jwtService *auth.JWTService,
+	refreshExpiry time.Duration,
+) *TokenService {
+	return &TokenService{
+		repo:          repo,
+		userRepo:      userRepo,
+		jwtService:    jwtService,
+		refreshExpiry: refreshExpiry,
+	}
+}
+
+// starts a new session, i.e. a new refresh token family; mfa records whether a second factor was given
+func (s *TokenService) Issue(user *models.User, mfa bool) (*models.TokenPair, error) {
+	familyID, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(user, familyID, mfa)
+}
+
+// swaps a refresh token for a new pair; presenting one that was already swapped revokes its whole family
+func (s *TokenService) Refresh(refreshToken string) (*models.TokenPair, error) {
+	now := time.Now()
+	stored, _ := s.repo.FindRefreshByHash(hashToken(refreshToken))
+	if stored == nil {
+		return nil, models.ErrInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		return nil, s.reused(stored, now)
+	}
+	if !now.Before(stored.ExpiresAt) {
+		return nil, models.ErrInvalidRefreshToken
+	}
+
+	consumed, err := s.repo.ConsumeRefresh(stored.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		// a concurrent request rotated it first
+		return nil, s.reused(stored, now)
+	}
+
+	user, _ := s.userRepo.FindByID(stored.UserID)
+	if user == nil {
+		return nil, models.ErrInvalidRefreshToken
+	}
+
+	return s.issue(user, stored.FamilyID, stored.MFA)
+}
+
+// ends the session behind the refresh token and denies the access token until it expires
+func (s *TokenService) Logout(userID uint, jti string, accessExpiresAt time.Time, refreshToken string) error {
+	now := time.Now()
+	if jti != "" && accessExpiresAt.After(now) {
+		if err := s.repo.Deny(&models.RevokedToken{JTI: jti, ExpiresAt: accessExpiresAt}); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken != "" {
+		stored, _ := s.repo.FindRefreshByHash(hashToken(refreshToken))
+		if stored != nil && stored.UserID == userID {
+			if err := s.repo.RevokeFamily(stored.FamilyID, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.repo.PurgeExpired(now)
+}
+
+// rejects access tokens that were logged out or belong to accounts that no longer exist
+func (s *TokenService) CheckAccess(userID uint, jti string) error {
+	if jti != "" {
+		denied, err := s.repo.IsDenied(jti)
+		if err != nil {
+			return err
+		}
+		if denied {
+			return models.ErrTokenRevoked
+		}
+	}
+
+	if user, _ := s.userRepo.FindByID(userID); user == nil {
+		return models.ErrTokenRevoked
+	}
+	return nil
+}
+
+// signs the user out everywhere once their refresh tokens can no longer be renewed
+func (s *TokenService) RevokeUser(userID uint) error {
+	return s.repo.RevokeAllForUser(userID, time.Now())
+}
+
+func (s *TokenService) issue(user *models.User, familyID string, mfa bool) (*models.TokenPair, error) {
+	accessToken, err := s.jwtService.GenerateToken(user, mfa)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(refreshToken),
+		MFA:       mfa,
+		ExpiresAt: time.Now().Add(s.refreshExpiry),
+	}
+	if err := s.repo.CreateRefresh(stored); err != nil {
+		return nil, err
+	}
+
+	return &models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.jwtService.Expiry().Seconds()),
+	}, nil
+}
+
+// treats a replayed refresh token as stolen and kills every token descended from the same login
+func (s *TokenService) reused(stored *models.RefreshToken, now time.Time) error {
+	if err := s.repo.RevokeFamily(stored.FamilyID, now); err != nil {
+		return errors.Join(models.ErrRefreshTokenReused, err)
+	}
+	return models.ErrRefreshTokenReused
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// refresh tokens are high-entropy, so a plain sha256 is enough to keep them unusable at rest
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prannvs/campus-leave-system/internal/auth"
+	"github.com/prannvs/campus-leave-system/internal/models"
+)
+
+// keeps refresh tokens in memory with the same consume and revoke semantics as the repository
+type memoryTokenStore struct {
+	tokens []*models.RefreshToken
+	denied map[string]bool
+	// makes the next consume lose, as when a concurrent refresh rotates the token first
+	loseRace bool
+}
+
+func (m *memoryTokenStore) CreateRefresh(token *models.RefreshToken) error {
+	token.ID = uint(len(m.tokens) + 1)
+	copied := *token
+	m.tokens = append(m.tokens, &copied)
+	return nil
+}
+
+func (m *memoryTokenStore) FindRefreshByHash(hash string) (*models.RefreshToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *memoryTokenStore) ConsumeRefresh(id uint, now time.Time) (bool, error) {
+	if m.loseRace {
+		m.loseRace = false
+		return false, nil
+	}
+	token := m.tokens[id-1]
+	if token.RevokedAt != nil {
+		return false, nil
+	}
+	token.RevokedAt = &now
+	return true, nil
+}
+
+func (m *memoryTokenStore) RevokeFamily(familyID string, now time.Time) error {
+	for _, token := range m.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *memoryTokenStore) RevokeAllForUser(userID uint, now time.Time) error {
+	for _, token := range m.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *memoryTokenStore) Deny(token *models.RevokedToken) error {
+	m.denied[token.JTI] = true
+	return nil
+}
+
+func (m *memoryTokenStore) IsDenied(jti string) (bool, error) {
+	return m.denied[jti], nil
+}
+
+func (m *memoryTokenStore) PurgeExpired(now time.Time) error {
+	return nil
+}
+
+// the live tokens of a family
+func (m *memoryTokenStore) live(familyID string) int {
+	count := 0
+	for _, token := range m.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			count++
+		}
+	}
+	return count
+}
+
+type memoryUsers map[uint]*models.User
+
+func (m memoryUsers) FindByID(id uint) (*models.User, error) {
+	if user, ok := m[id]; ok {
+		return user, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func newTestTokenService() (*TokenService, *memoryTokenStore, memoryUsers) {
+	store := &memoryTokenStore{denied: make(map[string]bool)}
+	users := memoryUsers{1: {ID: 1, Email: "student@example.com", Role: models.RoleStudent}}
+	svc := &TokenService{
+		repo:          store,
+		userRepo:      users,
+		jwtService:    auth.NewJWTService("test-secret", 15*time.Minute),
+		refreshExpiry: time.Hour,
+	}
+	return svc, store, users
+}
+
+func TestTokenServiceRefresh(t *testing.T) {
+	tests := []struct {
+		name string
+		// returns the refresh token to present
+		setup   func(t *testing.T, svc *TokenService, store *memoryTokenStore, users memoryUsers) string
+		wantErr error
+		// live tokens left in the session's family afterwards
+		wantLive int
+	}{
+		{
+			name: "rotates a live token",
+			setup: func(t *testing.T, svc *TokenService, store *memoryTokenStore, users memoryUsers) string {
+				return issue(t, svc, users[1]).RefreshToken
+			},
+			wantLive: 1,
+		},
+		{
+			name: "rejects an unknown token",
+			setup: func(t *testing.T, svc *TokenService, store *memoryTokenStore, users memoryUsers) string {
+				issue(t, svc, users[1])
+				return "not-a-token"
+			},
+			wantErr:  models.ErrInvalidRefreshToken,
+			wantLive: 1,
+		},
+		{
+			name: "rejects an expired token",
+			setup: func(t *testing.T, svc *TokenService, store *memoryTokenStore, users memoryUsers) string {
+				pair := issue(t, svc, users[1])
+				store.tokens[0].ExpiresAt = time.Now().Add(-time.Second)
+				return pair.RefreshToken
+			},
+			wantErr:  models.ErrInvalidRefreshToken,
+			wantLive: 1,
+		},
+		{
+			name: "replaying a rotated token revokes the family",
+			setup: func(t *testing.T, svc *TokenService, store *memoryTokenStore, users memoryUsers) string {
+				pair := issue(t, svc, users[1])
+				if _, err := svc.Refresh(pair.RefreshToken); err != nil {
+					t.Fatalf("first refresh: %v", err)
+				}
+				return pair.RefreshToken
+			},
+			wantErr:  models.ErrRefreshTokenReused,
+			wantLive: 0,
+		},
+		{
+			name: "losing a concurrent rotation counts as reuse",
+			setup: func(t *testing.T, svc *TokenService, store *memoryTokenStore, users memoryUsers) string {
+				pair := issue(t, svc, users[1])
+				store.loseRace = true
+				return pair.RefreshToken
+			},
+			wantErr:  models.ErrRefreshTokenReused,
+			wantLive: 0,
+		},
+		{
+			name: "rejects the token of a deleted account",
+			setup: func(t *testing.T, svc *TokenService, store *memoryTokenStore, users memoryUsers) string {
+				pair := issue(t, svc, users[1])
+				delete(users, 1)
+				return pair.RefreshToken
+			},
+			wantErr:  models.ErrInvalidRefreshToken,
+			wantLive: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, store, users := newTestTokenService()
+			presented := tt.setup(t, svc, store, users)
+
+			pair, err := svc.Refresh(presented)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Refresh() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (pair.RefreshToken == "" || pair.RefreshToken == presented) {
+				t.Errorf("Refresh() returned refresh token %q, want a fresh one", pair.RefreshToken)
+			}
+			if live := store.live(store.tokens[0].FamilyID); live != tt.wantLive {
+				t.Errorf("live tokens in family = %d, want %d", live, tt.wantLive)
+			}
+		})
+	}
+}
+
+func TestTokenServiceRefreshKeepsMFA(t *testing.T) {
+	for _, mfa := range []bool{false, true} {
+		svc, store, users := newTestTokenService()
+		pair, err := svc.Issue(users[1], mfa)
+		if err != nil {
+			t.Fatalf("Issue() error = %v", err)
+		}
+
+		if _, err := svc.Refresh(pair.RefreshToken); err != nil {
+			t.Fatalf("Refresh() error = %v", err)
+		}
+		rotated := store.tokens[len(store.tokens)-1]
+		if rotated.MFA != mfa || rotated.FamilyID != store.tokens[0].FamilyID {
+			t.Errorf("rotated token mfa = %v family = %q, want mfa = %v family = %q",
+				rotated.MFA, rotated.FamilyID, mfa, store.tokens[0].FamilyID)
+		}
+	}
+}
+
+func issue(t *testing.T, svc *TokenService, user *models.User) *models.TokenPair {
+	t.Helper()
+	pair, err := svc.Issue(user, false)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	return pair
+}
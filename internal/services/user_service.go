@@ -1,19 +1,45 @@
 package services
 
 import (
+	"log"
+	"sync"
+	"time"
+
 	"github.com/prannvs/campus-leave-system/internal/models"
 	"github.com/prannvs/campus-leave-system/internal/repositories"
 	"gorm.io/gorm"
 )
 
 type UserService struct {
-	repo *repositories.UserRepository
+	repo          *repositories.UserRepository
+	consentRepo   *repositories.ConsentRepository
+	invitationSvc *InvitationService
+	throttleSvc   *LoginThrottleService
 }
 
-func NewUserService(repo *repositories.UserRepository) *UserService {
-	return &UserService{repo: repo}
+func NewUserService(
+	repo *repositories.UserRepository,
+	consentRepo *repositories.ConsentRepository,
+	invitationSvc *InvitationService,
+	throttleSvc *LoginThrottleService,
+) *UserService {
+	return &UserService{
+		repo:          repo,
+		consentRepo:   consentRepo,
+		invitationSvc: invitationSvc,
+		throttleSvc:   throttleSvc,
+	}
 }
 
+// an account to check the password against when the email matches none, so both cases take as long
+var dummyUser = sync.OnceValue(func() *models.User {
+	user := &models.User{}
+	user.HashPassword("no account has this password")
+	return user
+})
+
+// creates the account; students may sign themselves up, every other role takes its
+// role and placement from a signed invitation for the same email
 func (s *UserService) Register(req models.RegisterRequest) (*models.User, error) {
 	existingUser, _ := s.repo.FindByEmail(req.Email)
 	if existingUser != nil {
@@ -26,36 +52,164 @@ func (s *UserService) Register(req models.RegisterRequest) (*models.User, error)
 		Role:   req.Role,
 		Dept:   req.Dept,
 		Hostel: req.Hostel,
+		Year:   req.Year,
+	}
+	if user.Role == "" {
+		user.Role = models.RoleStudent
 	}
 
 	if err := user.HashPassword(req.Password); err != nil {
 		return nil, err
 	}
 
+	if req.InvitationToken == "" {
+		if err := s.invitationSvc.CheckSelfRegistration(user.Role); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Create(user); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	invitation, err := s.invitationSvc.Redeem(req.InvitationToken, req.Email)
+	if err != nil {
+		return nil, err
+	}
+	user.Email = invitation.Email
+	user.Role = invitation.Role
+	if err := placeInvitee(user, invitation); err != nil {
+		s.releaseInvitation(invitation)
+		return nil, err
+	}
+	// the invitation link was mailed to this address, which proves it
+	verifiedAt := time.Now()
+	user.EmailVerifiedAt = &verifiedAt
+
 	if err := s.repo.Create(user); err != nil {
+		s.releaseInvitation(invitation)
+		return nil, err
+	}
+	if err := s.invitationSvc.Complete(invitation, user); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (s *UserService) Login(email, password string) (*models.User, error) {
-	user, err := s.repo.FindByEmail(email)
-	if err != nil {
-		return nil, models.ErrInvalidCredentials
+// staff approve for the department and hostel they were invited to, so only the invitation
+// sets those; students may fill in what their invitation leaves open
+func placeInvitee(user *models.User, invitation *models.Invitation) error {
+	if user.Role != models.RoleStudent {
+		if (user.Dept != "" && user.Dept != invitation.Dept) || (user.Hostel != "" && user.Hostel != invitation.Hostel) {
+			return models.ErrInvitationPlacement
+		}
+		user.Dept = invitation.Dept
+		user.Hostel = invitation.Hostel
+		return nil
+	}
+
+	if invitation.Dept != "" {
+		user.Dept = invitation.Dept
+	}
+	if invitation.Hostel != "" {
+		user.Hostel = invitation.Hostel
+	}
+	return nil
+}
+
+// frees a redeemed invitation whose account could not be created, so it can be used again
+func (s *UserService) releaseInvitation(invitation *models.Invitation) {
+	if err := s.invitationSvc.Complete(invitation, nil); err != nil {
+		log.Printf("Failed to release invitation %d: %v", invitation.ID, err)
+	}
+}
+
+// creates the first admin from configuration so invitations can be issued at all
+func (s *UserService) BootstrapAdmin(email, password string) error {
+	admins, err := s.repo.FindByRole(models.RoleAdmin)
+	if err != nil || len(admins) > 0 {
+		return err
 	}
 
-	if !user.CheckPassword(password) {
+	verifiedAt := time.Now()
+	admin := &models.User{Name: "Administrator", Email: email, Role: models.RoleAdmin, EmailVerifiedAt: &verifiedAt}
+	if err := admin.HashPassword(password); err != nil {
+		return err
+	}
+	return s.repo.Create(admin)
+}
+
+// checks the password, throttled per account email and per client address
+func (s *UserService) Login(email, password, ip string) (*models.User, error) {
+	if err := s.throttleSvc.Check(email, ip); err != nil {
+		return nil, err
+	}
+
+	user, _ := s.repo.FindByEmail(email)
+	if user == nil {
+		dummyUser().CheckPassword(password)
+	}
+
+	if user == nil || !user.CheckPassword(password) {
+		if err := s.throttleSvc.Fail(email, ip, user); err != nil {
+			return nil, err
+		}
 		return nil, models.ErrInvalidCredentials
 	}
 
+	if err := s.throttleSvc.Succeed(email); err != nil {
+		return nil, err
+	}
+
+	// move hashes made with an older work factor to the current one
+	if user.NeedsRehash() {
+		if err := user.HashPassword(password); err != nil {
+			return nil, err
+		}
+		if err := s.repo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+
 	return user, nil
 }
 
+// lifts a sign-in lockout on the account before it runs out
+func (s *UserService) Unlock(id uint) error {
+	user, err := s.repo.FindByID(id)
+	if err != nil {
+		return models.ErrUserNotFound
+	}
+	return s.throttleSvc.Unlock(user.Email)
+}
+
 func (s *UserService) GetByID(id uint) (*models.User, error) {
 	return s.repo.FindByID(id)
 }
 
+// the user as the viewer may see them; guardian contacts and date of birth only reach the user,
+// admins and approvers whose scope covers them
+func (s *UserService) View(id, viewerID uint) (*models.User, error) {
+	user, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+	if id == viewerID {
+		return user, nil
+	}
+
+	viewer, err := s.repo.FindByID(viewerID)
+	if err != nil {
+		return nil, models.ErrUnauthorized
+	}
+	// admins cover everyone, faculty their department and wardens their hostel
+	if viewer.Scope().Covers(user) {
+		return user, nil
+	}
+	return user.Public(), nil
+}
+
 func (s *UserService) GetAll(page, pageSize int) ([]models.User, int64, error) {
 	return s.repo.FindAll(page, pageSize)
 }
@@ -64,6 +218,56 @@ func (s *UserService) Update(user *models.User) error {
 	return s.repo.Update(user)
 }
 
+// sets a student's date of birth and guardian contacts; only an admin or the student's warden may,
+// and not while a guardian is still being asked about a leave
+func (s *UserService) UpdateGuardian(id, actorID uint, req models.GuardianInput) (*models.User, error) {
+	user, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	actor, err := s.repo.FindByID(actorID)
+	if err != nil {
+		return nil, models.ErrUnauthorized
+	}
+	allowed := actor.Role == models.RoleAdmin || actor.Role == models.RoleWarden && actor.Scope().Covers(user)
+	if !allowed || actor.ID == user.ID {
+		return nil, models.ErrUnauthorized
+	}
+
+	pending, err := s.consentRepo.HasPendingForStudent(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, models.ErrGuardianLocked
+	}
+
+	if err := applyGuardian(user, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func applyGuardian(user *models.User, req models.GuardianInput) error {
+	if req.DateOfBirth != "" {
+		dob, err := time.Parse("2006-01-02", req.DateOfBirth)
+		if err != nil {
+			return err
+		}
+		user.DateOfBirth = &dob
+	}
+
+	user.GuardianName = req.GuardianName
+	user.GuardianEmail = req.GuardianEmail
+	user.GuardianPhone = req.GuardianPhone
+	return nil
+}
+
 func (s *UserService) Delete(id uint) error {
 	return s.repo.Delete(id)
 }
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"github.com/prannvs/campus-leave-system/internal/models"
+	"github.com/prannvs/campus-leave-system/internal/repositories"
+	"gorm.io/gorm"
+)
+
+type WorkflowService struct {
+	repo *repositories.ApprovalRepository
+}
+
+func NewWorkflowService(repo *repositories.ApprovalRepository) *WorkflowService {
+	return &WorkflowService{repo: repo}
+}
+
+func (s *WorkflowService) WithTx(tx *gorm.DB) *WorkflowService {
+	return &WorkflowService{repo: s.repo.WithTx(tx)}
+}
+
+// returns the steps of the leave type's workflow that apply to the student, in order
+func (s *WorkflowService) StepsFor(leaveType models.LeaveType, student *models.User) ([]models.ApprovalStep, error) {
+	steps := models.DefaultApprovalSteps
+
+	workflow, err := s.repo.FindWorkflowByType(leaveType)
+	if err != nil {
+		return nil, err
+	}
+	if workflow != nil && len(workflow.Steps) > 0 {
+		steps = workflow.Steps
+	}
+
+	applicable := make([]models.ApprovalStep, 0, len(steps))
+	for _, step := range steps {
+		if step.AppliesTo(student) {
+			applicable = append(applicable, step)
+		}
+	}
+
+	// Nobody in the chain covers this student, so it falls to an admin
+	if len(applicable) == 0 {
+		applicable = append(applicable, models.ApprovalStep{StepOrder: 1, ApproverRole: models.RoleAdmin})
+	}
+	return applicable, nil
+}
+
+// retroactive requests skip the configured chain and go to faculty only
+func (s *WorkflowService) StepsForLeave(leave *models.LeaveRequest, student *models.User) ([]models.ApprovalStep, error) {
+	if leave.Retroactive {
+		return models.RetroactiveApprovalSteps, nil
+	}
+	return s.StepsFor(leave.LeaveType, student)
+}
+
+// returns the first step after the current one, nil when the chain is complete
+func (s *WorkflowService) NextStep(leave *models.LeaveRequest) (*models.ApprovalStep, error) {
+	steps, err := s.StepsForLeave(leave, &leave.Student)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range steps {
+		if steps[i].StepOrder > leave.CurrentStep {
+			return &steps[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *WorkflowService) RecordApproval(approval *models.LeaveApproval) error {
+	return s.repo.CreateApproval(approval)
+}
+
+func (s *WorkflowService) GetWorkflows() ([]models.ApprovalWorkflow, error) {
+	return s.repo.FindWorkflows()
+}
+
+func (s *WorkflowService) SaveWorkflow(leaveType models.LeaveType, req models.SaveWorkflowRequest) (*models.ApprovalWorkflow, error) {
+	workflow := &models.ApprovalWorkflow{LeaveType: leaveType}
+	for i, input := range req.Steps {
+		workflow.Steps = append(workflow.Steps, models.ApprovalStep{
+			StepOrder:    i + 1,
+			ApproverRole: models.Role(input.ApproverRole),
+			HostelOnly:   input.HostelOnly,
+		})
+	}
+
+	if err := s.repo.SaveWorkflow(workflow); err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
+
+// returns nil when the leave type uses the default chain
+func (s *WorkflowService) GetWorkflow(leaveType models.LeaveType) (*models.ApprovalWorkflow, error) {
+	return s.repo.FindWorkflowByType(leaveType)
+}
+
+func (s *WorkflowService) DeleteWorkflow(leaveType models.LeaveType) error {
+	return s.repo.DeleteWorkflow(leaveType)
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"fmt"
 	"log"
+	"slices"
 
 	"github.com/prannvs/campus-leave-system/internal/models"
 	"gorm.io/driver/postgres"
@@ -37,10 +38,25 @@ func InitDB(cfg Config) error {
 	DB = db
 	log.Println("Database connection established")
 
+	// Accounts created before email verification existed are trusted, once
+	verifyExisting := !DB.Migrator().HasColumn(&models.User{}, "EmailVerifiedAt")
+
 	if err := AutoMigrate(); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if verifyExisting {
+		if err := DB.Model(&models.User{}).
+			Where("email_verified_at IS NULL").
+			Update("email_verified_at", gorm.Expr("created_at")).Error; err != nil {
+			return fmt.Errorf("failed to backfill database: %w", err)
+		}
+	}
+
+	if err := Backfill(); err != nil {
+		return fmt.Errorf("failed to backfill database: %w", err)
+	}
+
 	return nil
 }
 
@@ -49,9 +65,98 @@ func AutoMigrate() error {
 		&models.User{},
 		&models.LeaveRequest{},
 		&models.Attendance{},
+		&models.LeaveQuota{},
+		&models.LeaveLedgerEntry{},
+		&models.ApprovalWorkflow{},
+		&models.ApprovalStep{},
+		&models.LeaveApproval{},
+		&models.LeaveAttachment{},
+		&models.AcademicTerm{},
+		&models.CalendarEvent{},
+		&models.WeeklyOff{},
+		&models.GatePass{},
+		&models.Delegation{},
+		&models.LeavePolicy{},
+		&models.PolicyBlackout{},
+		&models.LeaveTypeDefinition{},
+		&models.LeaveComment{},
+		&models.LeaveEvent{},
+		&models.LeaveOccurrence{},
+		&models.GuardianConsent{},
+		&models.Invitation{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+		&models.AccountToken{},
+		&models.RecoveryCode{},
+		&models.LoginThrottle{},
 	)
 }
 
+// brings rows created before newer columns existed in line with current defaults
+func Backfill() error {
+	// Requests filed before approval chains were waiting on the first faculty step
+	if err := DB.Model(&models.LeaveRequest{}).
+		Where("status = ? AND (awaiting_role IS NULL OR awaiting_role = '')", models.LeaveStatusPending).
+		Update("awaiting_role", models.RoleFaculty).Error; err != nil {
+		return err
+	}
+
+	// Older requests were counted in calendar days
+	if err := DB.Model(&models.LeaveRequest{}).
+		Where("days = 0").
+		Update("days", gorm.Expr("(end_date::date - start_date::date) + 1")).Error; err != nil {
+		return err
+	}
+
+	// Requests filed before the history was kept start it from the state they were in
+	if err := DB.Exec(`
+		INSERT INTO leave_events (leave_id, actor_id, action, to_status, step, created_at)
+		SELECT l.id, l.approved_by, ?, l.status, l.current_step, l.updated_at
+		FROM leave_requests l
+		WHERE NOT EXISTS (SELECT 1 FROM leave_events e WHERE e.leave_id = l.id)
+	`, models.LeaveEventRecorded).Error; err != nil {
+		return err
+	}
+
+	return seedLeaveTypes()
+}
+
+// fills an empty catalogue with the built-in types plus any other codes already on file, kept inactive
+func seedLeaveTypes() error {
+	var count int64
+	if err := DB.Model(&models.LeaveTypeDefinition{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	leaveTypes := append([]models.LeaveTypeDefinition{}, models.DefaultLeaveTypes...)
+
+	var codes, unknown []string
+	if err := DB.Model(&models.LeaveRequest{}).Distinct().Pluck("leave_type", &codes).Error; err != nil {
+		return err
+	}
+	for _, code := range codes {
+		if !slices.ContainsFunc(leaveTypes, func(t models.LeaveTypeDefinition) bool { return string(t.Code) == code }) {
+			leaveTypes = append(leaveTypes, models.LeaveTypeDefinition{Code: models.LeaveType(code), Name: code})
+			unknown = append(unknown, code)
+		}
+	}
+
+	if err := DB.Create(&leaveTypes).Error; err != nil {
+		return err
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	// Active defaults to true on insert, so retire the unknown codes afterwards
+	return DB.Model(&models.LeaveTypeDefinition{}).
+		Where("code IN ?", unknown).
+		Update("active", false).Error
+}
+
 func GetDB() *gorm.DB {
 	return DB
 }